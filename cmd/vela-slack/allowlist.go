@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// validateAllowlist validates the webhook, Slack API url and channel
+// are allowed when an allowlist was provided by an administrator.
+func (p *Plugin) validateAllowlist() error {
+	hosts := splitList(p.AllowedHosts)
+
+	if len(hosts) > 0 {
+		if len(p.Webhook) > 0 {
+			err := checkHost(p.Webhook, hosts)
+			if err != nil {
+				return fmt.Errorf("webhook not allowed: %w", err)
+			}
+		}
+
+		if len(p.APIURL) > 0 && p.APIURL != slack.APIURL {
+			err := checkHost(p.APIURL, hosts)
+			if err != nil {
+				return fmt.Errorf("api url not allowed: %w", err)
+			}
+		}
+	}
+
+	channels := splitList(p.AllowedChannels)
+
+	if len(channels) > 0 && len(p.WebhookMsg.Channel) > 0 {
+		// the rendered channel can't be checked before the message is created
+		if strings.Contains(p.WebhookMsg.Channel, "{{") {
+			return fmt.Errorf("channel templates are not supported with a channel allowlist")
+		}
+
+		if !channelAllowed(p.WebhookMsg.Channel, channels) {
+			return fmt.Errorf("channel not allowed: %s", p.WebhookMsg.Channel)
+		}
+	}
+
+	return nil
+}
+
+// checkHost checks the host of the url is in the list of
+// hosts, where *.example.com allows any subdomain.
+func checkHost(rawURL string, hosts []string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+
+	host := strings.ToLower(u.Hostname())
+
+	for _, allowed := range hosts {
+		allowed = strings.ToLower(allowed)
+
+		if host == allowed {
+			return nil
+		}
+
+		if suffix, ok := strings.CutPrefix(allowed, "*"); ok && strings.HasPrefix(suffix, ".") && strings.HasSuffix(host, suffix) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("host %q is not in the allowed hosts", host)
+}
+
+// channelAllowed checks if the channel name or id is in the list of channels.
+func channelAllowed(channel string, channels []string) bool {
+	channel = strings.TrimPrefix(channel, "#")
+
+	for _, allowed := range channels {
+		if strings.EqualFold(channel, strings.TrimPrefix(allowed, "#")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitList splits the values on commas, newlines and spaces
+// so lists can be provided one entry per line in a file.
+func splitList(values []string) []string {
+	var list []string
+
+	for _, value := range values {
+		list = append(list, strings.FieldsFunc(value, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\n' || r == '\r' || r == '\t'
+		})...)
+	}
+
+	return list
+}
+
+// checkChannel validates that the rendered channel is allowed since the
+// message template can change the channel after the configuration was
+// validated. Direct messages and webhooks without a channel are allowed.
+func (p *Plugin) checkChannel(channel string) error {
+	channels := splitList(p.AllowedChannels)
+
+	if len(channels) == 0 || len(channel) == 0 || p.DMAuthor {
+		return nil
+	}
+
+	if !channelAllowed(channel, channels) {
+		return fmt.Errorf("channel not allowed: %s", channel)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_Plugin_Validate_Allowlist(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		webhook  string
+		apiURL   string
+		channel  string
+		channels []string
+		hosts    []string
+		failure  bool
+	}{
+		{
+			name:    "no allowlist",
+			webhook: "https://attacker.example.com/hook",
+			channel: "#anything",
+		},
+		{
+			name:    "allowed host",
+			webhook: "https://hooks.slack.com/services/T0/B0/abc",
+			hosts:   []string{"hooks.slack.com"},
+		},
+		{
+			name:    "allowed wildcard host",
+			webhook: "https://chat.example.com/hooks/abc",
+			hosts:   []string{"*.example.com"},
+		},
+		{
+			name:    "disallowed host",
+			webhook: "https://attacker.example.org/hook",
+			hosts:   []string{"hooks.slack.com", "*.example.com"},
+			failure: true,
+		},
+		{
+			name:    "disallowed api url",
+			webhook: "https://hooks.slack.com/services/T0/B0/abc",
+			apiURL:  "https://attacker.example.org/api/",
+			hosts:   []string{"hooks.slack.com"},
+			failure: true,
+		},
+		{
+			name:     "allowed channel",
+			webhook:  "https://hooks.slack.com/services/T0/B0/abc",
+			channel:  "#Builds",
+			channels: []string{"builds\ndeploys"},
+		},
+		{
+			name:     "disallowed channel",
+			webhook:  "https://hooks.slack.com/services/T0/B0/abc",
+			channel:  "#random",
+			channels: []string{"builds", "deploys"},
+			failure:  true,
+		},
+		{
+			name:     "channel template",
+			webhook:  "https://hooks.slack.com/services/T0/B0/abc",
+			channel:  "{{ .BuildBranch }}",
+			channels: []string{"builds"},
+			failure:  true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		p := &Plugin{
+			Webhook:         test.webhook,
+			APIURL:          test.apiURL,
+			AllowedChannels: test.channels,
+			AllowedHosts:    test.hosts,
+			Env:             &Env{},
+			WebhookMsg: &slack.WebhookMessage{
+				Channel: test.channel,
+				Text:    "hello",
+			},
+		}
+
+		err := p.Validate()
+
+		if test.failure && err == nil {
+			t.Errorf("Validate should have returned err for %s", test.name)
+		}
+
+		if !test.failure && err != nil {
+			t.Errorf("Validate returned err for %s: %v", test.name, err)
+		}
+	}
+}
+
+func TestSlack_splitList(t *testing.T) {
+	// setup types
+	want := []string{"builds", "deploys", "C024BE91L"}
+
+	got := splitList([]string{"builds, deploys\n", "C024BE91L"})
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitList is %v, want %v", got, want)
+	}
+}
+
+func TestSlack_Plugin_Exec_Allowlist_Rendered_Channel(t *testing.T) {
+	// setup types
+	var channels []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			t.Errorf("ParseForm error: %v", err)
+		}
+
+		channels = append(channels, r.PostForm.Get("channel"))
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"ok": true, "channel": "C024BE91L", "ts": "1503435956.000247"}`)
+	}))
+	defer ts.Close()
+
+	// setup tests
+	tests := []struct {
+		name    string
+		text    string
+		failure bool
+	}{
+		{name: "allowed", text: "hello"},
+		{name: "duplicate channel key", text: "{{ printf \"%c,%cchannel%c:%c#random\" 34 34 34 34 }}", failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		channels = nil
+
+		p := &Plugin{
+			Env:             &Env{},
+			BotToken:        "xoxb-token",
+			APIURL:          ts.URL + "/",
+			AllowedChannels: []string{"#builds"},
+			WebhookMsg: &slack.WebhookMessage{
+				Channel: "#builds",
+				Text:    test.text,
+			},
+		}
+
+		err := p.Validate()
+		if err != nil {
+			t.Errorf("Validate returned err for %s: %v", test.name, err)
+		}
+
+		err = p.Exec()
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("Exec should have returned err for %s", test.name)
+			}
+
+			if len(channels) > 0 {
+				t.Errorf("Exec posted to %v for %s", channels, test.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Exec returned err for %s: %v", test.name, err)
+		}
+
+		if !reflect.DeepEqual(channels, []string{"#builds"}) {
+			t.Errorf("Exec posted to %v for %s, want #builds", channels, test.name)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/slack-go/slack"
+)
+
+// client creates a Slack Web API client using the bot token.
+func (p *Plugin) client() *slack.Client {
+	opts := []slack.Option{slack.OptionHTTPClient(p.httpClient())}
+
+	if len(p.APIURL) > 0 {
+		opts = append(opts, slack.OptionAPIURL(p.APIURL))
+	}
+
+	return slack.New(p.BotToken, opts...)
+}
+
+// msgOptions converts the webhook message into
+// options for sending with the Slack Web API.
+func msgOptions(msg *slack.WebhookMessage) []slack.MsgOption {
+	opts := []slack.MsgOption{
+		slack.MsgOptionText(msg.Text, false),
+		slack.MsgOptionAttachments(msg.Attachments...),
+	}
+
+	if msg.Blocks != nil {
+		opts = append(opts, slack.MsgOptionBlocks(msg.Blocks.BlockSet...))
+	}
+
+	if len(msg.Username) > 0 {
+		opts = append(opts, slack.MsgOptionUsername(msg.Username))
+	}
+
+	if len(msg.IconEmoji) > 0 {
+		opts = append(opts, slack.MsgOptionIconEmoji(msg.IconEmoji))
+	}
+
+	if len(msg.IconURL) > 0 {
+		opts = append(opts, slack.MsgOptionIconURL(msg.IconURL))
+	}
+
+	if len(msg.ThreadTimestamp) > 0 {
+		opts = append(opts, slack.MsgOptionTS(msg.ThreadTimestamp))
+	}
+
+	if len(msg.Parse) > 0 {
+		opts = append(opts, slack.MsgOptionParse(msg.Parse == "full"))
+	}
+
+	return opts
+}
+
+// messageRef references a message posted to Slack.
+type messageRef struct {
+	Channel            string `json:"channel"`
+	Timestamp          string `json:"ts,omitempty"`
+	ScheduledMessageID string `json:"scheduled_message_id,omitempty"`
+}
+
+// readMessageRef reads the message reference stored in the file.
+func readMessageRef(path string) (*messageRef, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read message file %s: %w", path, err)
+	}
+
+	ref := new(messageRef)
+
+	err = json.Unmarshal(data, ref)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse message file %s: %w", path, err)
+	}
+
+	return ref, nil
+}
+
+// writeMessageRef stores the message reference in the file
+// so it can be used by later steps in the pipeline.
+func writeMessageRef(path string, ref *messageRef) error {
+	data, err := json.Marshal(ref)
+	if err != nil {
+		return fmt.Errorf("unable to marshal message reference: %w", err)
+	}
+
+	err = os.WriteFile(path, data, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to write message file %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+
+	registry "github.com/go-vela/server/compiler/registry/github"
+)
+
+// comparePerPage is the most commits requested per page of a comparison.
+const comparePerPage = 100
+
+// Commit represents a commit in the changelog of the build.
+type Commit struct {
+	SHA     string
+	Author  string
+	Subject string
+	Link    string
+}
+
+// githubClient creates a GitHub client for the registry
+// using the token and the configured HTTP client.
+func (p *Plugin) githubClient(ctx context.Context) (*github.Client, error) {
+	reg, err := registry.New(ctx, p.Env.RegistryURL, p.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	// the unauthenticated GitHub client is created without the context
+	if len(p.Token) == 0 {
+		return p.unauthenticatedGitHub(reg.Github.BaseURL), nil
+	}
+
+	return reg.Github, nil
+}
+
+// unauthenticatedGitHub creates a GitHub client for the API
+// url without a token using the configured HTTP client.
+func (p *Plugin) unauthenticatedGitHub(baseURL *url.URL) *github.Client {
+	gh := github.NewClient(p.httpClient())
+	gh.BaseURL = baseURL
+
+	return gh
+}
+
+// loadChangelog adds the commits since the changelog base, or the
+// previous commit with a successful status, to the environment.
+// Problems creating the changelog are logged so the message is still sent.
+func (p *Plugin) loadChangelog() {
+	if !p.Changelog {
+		return
+	}
+
+	commits, err := p.changelog(context.Background())
+	if err != nil {
+		logrus.Warnf("unable to create changelog: %v", err)
+
+		return
+	}
+
+	p.Env.Commits = commits
+}
+
+// changelog returns the commits between the base and the build commit.
+func (p *Plugin) changelog(ctx context.Context) ([]Commit, error) {
+	if p.ChangelogLimit <= 0 {
+		return nil, fmt.Errorf("invalid changelog limit provided: %d", p.ChangelogLimit)
+	}
+
+	// use the configured client for the authenticated GitHub client
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.httpClient())
+
+	gh, err := p.githubClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, repo := p.Env.RepositoryOrg, p.Env.RepositoryName
+
+	base, err := renderTemplate("changelog_base", p.ChangelogBase, p.Env)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(base) == 0 {
+		base, err = previousSuccess(ctx, gh, owner, repo, p.Env.BuildCommit, p.ChangelogLimit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	logrus.Infof("Comparing %s to %s for changelog...", base, p.Env.BuildCommit)
+
+	compared, err := compareCommits(ctx, gh, owner, repo, base, p.Env.BuildCommit, p.ChangelogLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]Commit, 0, len(compared))
+
+	for _, c := range compared {
+		subject, _, _ := strings.Cut(c.GetCommit().GetMessage(), "\n")
+
+		// the template is rendered inside JSON so the text must be escaped
+		commits = append(commits, Commit{
+			SHA:     c.GetSHA(),
+			Author:  escapeJSON(c.GetCommit().GetAuthor().GetName()),
+			Subject: escapeJSON(subject),
+			Link:    c.GetHTMLURL(),
+		})
+	}
+
+	return commits, nil
+}
+
+// compareCommits returns the newest commits, up to the limit, between the
+// base and head. GitHub lists compared commits oldest first, so the pages
+// are read from the last page back until the limit is reached.
+func compareCommits(ctx context.Context, gh *github.Client, owner, repo, base, head string, limit int) ([]*github.RepositoryCommit, error) {
+	compare := func(page int) (*github.CommitsComparison, error) {
+		comparison, _, err := gh.Repositories.CompareCommits(ctx, owner, repo, base, head, &github.ListOptions{
+			Page:    page,
+			PerPage: comparePerPage,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to compare commits: %w", err)
+		}
+
+		return comparison, nil
+	}
+
+	first, err := compare(1)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := first.Commits
+	last := (first.GetTotalCommits() + comparePerPage - 1) / comparePerPage
+
+	if last > 1 {
+		commits = nil
+
+		for page := last; page > 0 && len(commits) < limit; page-- {
+			comparison := first
+
+			if page > 1 {
+				comparison, err = compare(page)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			commits = append(comparison.Commits, commits...)
+		}
+	}
+
+	if len(commits) > limit {
+		commits = commits[len(commits)-limit:]
+	}
+
+	return commits, nil
+}
+
+// previousSuccess returns the most recent ancestor of the commit
+// with a successful combined status, checking up to limit commits.
+func previousSuccess(ctx context.Context, gh *github.Client, owner, repo, sha string, limit int) (string, error) {
+	commits, _, err := gh.Repositories.ListCommits(ctx, owner, repo, &github.CommitsListOptions{
+		SHA:         sha,
+		ListOptions: github.ListOptions{PerPage: limit + 1},
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to list commits: %w", err)
+	}
+
+	for _, c := range commits {
+		// skip the commit being built
+		if c.GetSHA() == sha {
+			continue
+		}
+
+		status, _, err := gh.Repositories.GetCombinedStatus(ctx, owner, repo, c.GetSHA(), nil)
+		if err != nil {
+			return "", fmt.Errorf("unable to get status for commit %s: %w", c.GetSHA(), err)
+		}
+
+		if status.GetState() == "success" {
+			return c.GetSHA(), nil
+		}
+	}
+
+	return "", fmt.Errorf("no successful commit found in the last %d commits", limit)
+}
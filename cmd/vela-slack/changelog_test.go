@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+// newGitHubServer creates a GitHub API server with the head commit
+// and two previous commits, where only the oldest commit succeeded.
+func newGitHubServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/v3/repos/octocat/hello-world/commits", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"sha": "c3"}, {"sha": "c2"}, {"sha": "c1"}]`)
+	})
+
+	mux.HandleFunc("/api/v3/repos/octocat/hello-world/commits/c2/status", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"state": "failure"}`)
+	})
+
+	mux.HandleFunc("/api/v3/repos/octocat/hello-world/commits/c1/status", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"state": "success"}`)
+	})
+
+	mux.HandleFunc("/api/v3/repos/octocat/hello-world/compare/", func(w http.ResponseWriter, r *http.Request) {
+		base := r.URL.Path[len("/api/v3/repos/octocat/hello-world/compare/"):]
+
+		commits := []map[string]interface{}{
+			{
+				"sha":      "c2",
+				"html_url": "https://github.com/octocat/hello-world/commit/c2",
+				"commit":   map[string]interface{}{"message": "Add \"retry\" support\n\nDetails", "author": map[string]string{"name": "Octo Cat"}},
+			},
+			{
+				"sha":      "c3",
+				"html_url": "https://github.com/octocat/hello-world/commit/c3",
+				"commit":   map[string]interface{}{"message": "Fix retry", "author": map[string]string{"name": "Mona"}},
+			},
+		}
+
+		switch base {
+		case "c1...c3":
+		case "v1.0.0...c3":
+			commits = commits[1:]
+		case "v0.1.0...c3":
+			// a long history listed oldest first
+			commits = nil
+
+			for i := 1; i <= 250; i++ {
+				commits = append(commits, map[string]interface{}{
+					"sha":    fmt.Sprintf("h%d", i),
+					"commit": map[string]interface{}{"message": fmt.Sprintf("Change %d", i)},
+				})
+			}
+		default:
+			http.NotFound(w, r)
+
+			return
+		}
+
+		// paginate the commits like GitHub
+		total := len(commits)
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		if perPage > 0 && page > 0 {
+			start := min((page-1)*perPage, total)
+			commits = commits[start:min(start+perPage, total)]
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"total_commits": total, "commits": commits})
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestSlack_Plugin_changelog(t *testing.T) {
+	// setup types
+	ts := newGitHubServer(t)
+	defer ts.Close()
+
+	// the newest commits of a comparison over several pages
+	var newest []Commit
+
+	for i := 131; i <= 250; i++ {
+		newest = append(newest, Commit{SHA: fmt.Sprintf("h%d", i), Subject: fmt.Sprintf("Change %d", i)})
+	}
+
+	// setup tests
+	tests := []struct {
+		base  string
+		limit int
+		want  []Commit
+	}{
+		{
+			limit: 50,
+			want: []Commit{
+				{SHA: "c2", Author: "Octo Cat", Subject: `Add \"retry\" support`, Link: "https://github.com/octocat/hello-world/commit/c2"},
+				{SHA: "c3", Author: "Mona", Subject: "Fix retry", Link: "https://github.com/octocat/hello-world/commit/c3"},
+			},
+		},
+		{
+			limit: 1,
+			want: []Commit{
+				{SHA: "c3", Author: "Mona", Subject: "Fix retry", Link: "https://github.com/octocat/hello-world/commit/c3"},
+			},
+		},
+		{
+			base:  "{{ .BuildTag }}",
+			limit: 50,
+			want: []Commit{
+				{SHA: "c3", Author: "Mona", Subject: "Fix retry", Link: "https://github.com/octocat/hello-world/commit/c3"},
+			},
+		},
+		{
+			base:  "v0.1.0",
+			limit: 120,
+			want:  newest,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		p := &Plugin{
+			Token:          "ghp_token",
+			Changelog:      true,
+			ChangelogBase:  test.base,
+			ChangelogLimit: test.limit,
+			Env: &Env{
+				BuildCommit:    "c3",
+				BuildTag:       "v1.0.0",
+				RegistryURL:    ts.URL,
+				RepositoryOrg:  "octocat",
+				RepositoryName: "hello-world",
+			},
+		}
+
+		got, err := p.changelog(context.Background())
+		if err != nil {
+			t.Errorf("changelog returned err: %v", err)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("changelog is %+v, want %+v", got, test.want)
+		}
+	}
+}
+
+func TestSlack_Plugin_Exec_Changelog(t *testing.T) {
+	// setup types
+	gh := newGitHubServer(t)
+	defer gh.Close()
+
+	var posted slack.WebhookMessage
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&posted)
+		if err != nil {
+			t.Errorf("Decode error: %v", err)
+		}
+	}))
+	defer ts.Close()
+
+	p := &Plugin{
+		Webhook:        ts.URL,
+		Changelog:      true,
+		ChangelogLimit: 50,
+		Env: &Env{
+			BuildCommit:    "c3",
+			RegistryURL:    gh.URL,
+			RepositoryOrg:  "octocat",
+			RepositoryName: "hello-world",
+		},
+		WebhookMsg: &slack.WebhookMessage{
+			Text: "{{ range .Commits }}• {{ .Subject }} ({{ .Author }})\n{{ end }}",
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	want := "• Add \"retry\" support (Octo Cat)\n• Fix retry (Mona)\n"
+
+	if posted.Text != want {
+		t.Errorf("Exec posted text %q, want %q", posted.Text, want)
+	}
+}
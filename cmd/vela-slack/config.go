@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// parameterPrefix is the prefix of the environment variables
+// Vela sets for the parameters of a step.
+const parameterPrefix = "PARAMETER_"
+
+// loadConfig applies the parameters from the config file to the flags
+// that weren't set, so explicit parameters win over the config file.
+func loadConfig(c *cli.Context) error {
+	path := c.String("config")
+	if len(path) == 0 {
+		return nil
+	}
+
+	data, err := readConfig(c, path)
+	if err != nil {
+		return err
+	}
+
+	config := make(map[string]interface{})
+
+	err = yaml.Unmarshal(data, &config)
+	if err != nil {
+		return fmt.Errorf("unable to parse config file %s: %w", path, err)
+	}
+
+	logrus.Infof("Using parameters from config file %s", path)
+
+	return applyConfig(c, config)
+}
+
+// readConfig reads the config file from the workspace or,
+// with config_remote, from the registry.
+func readConfig(c *cli.Context, path string) ([]byte, error) {
+	if !c.Bool("config-remote") {
+		path = workspacePath(c.String("build-workspace"), path)
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read config file %s: %w", path, err)
+		}
+
+		return data, nil
+	}
+
+	client, err := newHTTPClient(c.Duration("timeout"), c.String("proxy"), c.String("sslcert.path"))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Plugin{
+		Token:      c.String("token"),
+		HTTPClient: client,
+		Env:        &Env{RegistryURL: c.String("registry-url")},
+	}
+
+	return p.fetchTemplate(path)
+}
+
+// applyConfig sets the flags of the parameters in the config
+// that weren't set with the step parameters or environment.
+func applyConfig(c *cli.Context, config map[string]interface{}) error {
+	flags := parameterFlags(c.App.Flags)
+
+	// apply the parameters in a stable order
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		flag, ok := flags[key]
+		if !ok || key == "config" || key == "config_remote" {
+			return fmt.Errorf("unknown parameter in config file: %s", key)
+		}
+
+		name := flag.Names()[0]
+
+		if c.IsSet(name) {
+			logrus.Debugf("Using parameter %s over the config file", key)
+
+			continue
+		}
+
+		values, err := configValues(flag, config[key])
+		if err != nil {
+			return fmt.Errorf("invalid parameter %s in config file: %w", key, err)
+		}
+
+		for _, value := range values {
+			err = c.Set(name, value)
+			if err != nil {
+				return fmt.Errorf("invalid parameter %s in config file: %w", key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parameterFlags returns the flags by the name of their
+// step parameter, e.g. quiet_hours for PARAMETER_QUIET_HOURS.
+func parameterFlags(flags []cli.Flag) map[string]cli.Flag {
+	params := make(map[string]cli.Flag)
+
+	for _, flag := range flags {
+		f, ok := flag.(interface{ GetEnvVars() []string })
+		if !ok {
+			continue
+		}
+
+		for _, env := range f.GetEnvVars() {
+			if strings.HasPrefix(env, parameterPrefix) {
+				params[strings.ToLower(strings.TrimPrefix(env, parameterPrefix))] = flag
+			}
+		}
+	}
+
+	return params
+}
+
+// configValues converts the value from the config file to flag values.
+// Like Vela does for step parameters, lists and maps are passed as JSON
+// except for list flags which get each item.
+func configValues(flag cli.Flag, value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		if _, ok := flag.(*cli.StringSliceFlag); ok {
+			values := make([]string, 0, len(v))
+
+			for _, item := range v {
+				values = append(values, fmt.Sprint(item))
+			}
+
+			return values, nil
+		}
+
+		return jsonValue(v)
+	case map[string]interface{}:
+		return jsonValue(v)
+	default:
+		return []string{fmt.Sprint(v)}, nil
+	}
+}
+
+// jsonValue marshals the value to a JSON flag value.
+func jsonValue(value interface{}) ([]string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal value: %w", err)
+	}
+
+	return []string{string(data)}, nil
+}
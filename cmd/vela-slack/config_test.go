@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+// newConfigApp creates an app with some of the plugin flags
+// that runs the action after applying the config file.
+func newConfigApp(action func(c *cli.Context)) *cli.App {
+	app := cli.NewApp()
+
+	app.Flags = []cli.Flag{
+		&cli.StringFlag{EnvVars: []string{"PARAMETER_CONFIG"}, Name: "config"},
+		&cli.BoolFlag{EnvVars: []string{"PARAMETER_CONFIG_REMOTE"}, Name: "config-remote"},
+		&cli.StringFlag{EnvVars: []string{"VELA_BUILD_WORKSPACE"}, Name: "build-workspace"},
+		&cli.StringFlag{EnvVars: []string{"PARAMETER_CHANNEL", "SLACK_CHANNEL"}, Name: "channel"},
+		&cli.StringFlag{EnvVars: []string{"PARAMETER_TEXT", "SLACK_TEXT"}, Name: "text"},
+		&cli.BoolFlag{EnvVars: []string{"PARAMETER_CHANGELOG", "SLACK_CHANGELOG"}, Name: "changelog"},
+		&cli.IntFlag{EnvVars: []string{"PARAMETER_CHANGELOG_LIMIT"}, Name: "changelog-limit", Value: 50},
+		&cli.StringSliceFlag{EnvVars: []string{"PARAMETER_QUIET_HOURS"}, Name: "quiet-hours"},
+		&cli.StringFlag{EnvVars: []string{"PARAMETER_ROUTES"}, Name: "routes"},
+		&cli.StringFlag{EnvVars: []string{"PARAMETER_LOG_FORMAT"}, Name: "log.format"},
+	}
+
+	app.Action = func(c *cli.Context) error {
+		err := loadConfig(c)
+		if err != nil {
+			return err
+		}
+
+		action(c)
+
+		return nil
+	}
+
+	return app
+}
+
+func TestSlack_loadConfig(t *testing.T) {
+	// setup types
+	workspace := t.TempDir()
+
+	config := `
+channel: "#builds"
+text: "{{ .RepositoryFullName }} {{ .BuildStatus }}"
+changelog: true
+changelog_limit: 10
+log_format: json
+quiet_hours:
+  - Mon-Fri 18:00-08:00
+  - Sat,Sun
+routes:
+  - status: failure
+    channel: "#oncall"
+`
+
+	err := os.WriteFile(filepath.Join(workspace, ".slack.yml"), []byte(config), 0o600)
+	if err != nil {
+		t.Errorf("WriteFile returned err: %v", err)
+	}
+
+	t.Setenv("PARAMETER_CONFIG", ".slack.yml")
+	t.Setenv("VELA_BUILD_WORKSPACE", workspace)
+
+	// explicit parameters win over the config file
+	t.Setenv("PARAMETER_CHANNEL", "#explicit")
+
+	var (
+		channel    string
+		text       string
+		changelog  bool
+		limit      int
+		quietHours []string
+		routes     string
+		logFormat  string
+	)
+
+	app := newConfigApp(func(c *cli.Context) {
+		channel = c.String("channel")
+		text = c.String("text")
+		changelog = c.Bool("changelog")
+		limit = c.Int("changelog-limit")
+		quietHours = c.StringSlice("quiet-hours")
+		routes = c.String("routes")
+		logFormat = c.String("log.format")
+	})
+
+	// run test
+	err = app.Run([]string{"vela-slack"})
+	if err != nil {
+		t.Errorf("Run returned err: %v", err)
+	}
+
+	if channel != "#explicit" {
+		t.Errorf("channel is %s, want #explicit", channel)
+	}
+
+	if text != "{{ .RepositoryFullName }} {{ .BuildStatus }}" {
+		t.Errorf("text is %s", text)
+	}
+
+	if !changelog || limit != 10 || logFormat != "json" {
+		t.Errorf("changelog is %v with limit %d and log format %s, want true with 10 and json", changelog, limit, logFormat)
+	}
+
+	// list items are split on commas like list parameters
+	if !reflect.DeepEqual(quietHours, []string{"Mon-Fri 18:00-08:00", "Sat", "Sun"}) {
+		t.Errorf("quiet hours are %v", quietHours)
+	}
+
+	got, err := parseRoutes(routes)
+	if err != nil {
+		t.Errorf("parseRoutes returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, []Route{{Status: "failure", Channel: "#oncall"}}) {
+		t.Errorf("routes are %+v", got)
+	}
+}
+
+func TestSlack_loadConfig_Errors(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{name: "unknown parameter", config: "chanel: \"#builds\""},
+		{name: "build environment", config: "build_workspace: /tmp"},
+		{name: "nested config", config: "config: other.yml"},
+		{name: "invalid value", config: "changelog_limit: many"},
+		{name: "invalid yaml", config: "channel: [\"#builds\""},
+	}
+
+	// run tests
+	for _, test := range tests {
+		workspace := t.TempDir()
+
+		err := os.WriteFile(filepath.Join(workspace, ".slack.yml"), []byte(test.config), 0o600)
+		if err != nil {
+			t.Errorf("WriteFile returned err: %v", err)
+		}
+
+		t.Setenv("PARAMETER_CONFIG", ".slack.yml")
+		t.Setenv("VELA_BUILD_WORKSPACE", workspace)
+
+		app := newConfigApp(func(c *cli.Context) {})
+
+		err = app.Run([]string{"vela-slack"})
+		if err == nil {
+			t.Errorf("Run should have returned err for %s", test.name)
+		}
+	}
+
+	// missing config file
+	t.Setenv("PARAMETER_CONFIG", "missing.yml")
+
+	err := newConfigApp(func(c *cli.Context) {}).Run([]string{"vela-slack"})
+	if err == nil {
+		t.Errorf("Run should have returned err for a missing config file")
+	}
+}
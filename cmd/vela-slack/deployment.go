@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	api "github.com/go-vela/server/api/types"
+)
+
+const (
+	// eventDeployment is the build event for deployments.
+	eventDeployment = "deployment"
+
+	// deploymentParameterPrefix is the prefix of the variables
+	// Vela injects for the deployment payload.
+	deploymentParameterPrefix = "DEPLOYMENT_PARAMETER_"
+)
+
+// Deployment represents the deployment that triggered the build.
+type Deployment struct {
+	Number      int
+	Target      string
+	Task        string
+	Description string
+	Parameters  map[string]string
+}
+
+// deploymentParameters returns the deployment payload from the
+// DEPLOYMENT_PARAMETER_* variables in the environment. The values
+// are escaped so they can be placed in the JSON message template.
+func deploymentParameters(environ []string) map[string]string {
+	params := make(map[string]string)
+
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+
+		key, ok := strings.CutPrefix(name, deploymentParameterPrefix)
+		if !ok || len(key) == 0 {
+			continue
+		}
+
+		params[key] = escapeJSON(value)
+	}
+
+	return params
+}
+
+// loadDeploymentTask adds the task of the deployment from the Vela API
+// to the environment since Vela doesn't set it for the build. Problems
+// reading the deployment are logged so the message is still sent.
+func (p *Plugin) loadDeploymentTask() {
+	if !p.VelaAPI || p.Env.Deployment.Number == 0 || len(p.Env.Deployment.Task) > 0 {
+		return
+	}
+
+	task, err := p.deploymentTask(context.Background())
+	if err != nil {
+		logrus.Warnf("unable to read deployment from Vela API: %v", err)
+
+		return
+	}
+
+	p.Env.Deployment.Task = task
+}
+
+// deploymentTask returns the task of the deployment from the Vela API.
+func (p *Plugin) deploymentTask(ctx context.Context) (string, error) {
+	path := fmt.Sprintf(
+		"/api/v1/deployments/%s/%s/%d",
+		url.PathEscape(p.Env.RepositoryOrg), url.PathEscape(p.Env.RepositoryName), p.Env.Deployment.Number,
+	)
+
+	var d api.Deployment
+
+	err := p.velaGet(ctx, path, nil, &d)
+	if err != nil {
+		return "", err
+	}
+
+	// the template is rendered inside JSON so the text must be escaped
+	return escapeJSON(d.GetTask()), nil
+}
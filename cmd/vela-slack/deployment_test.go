@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/urfave/cli/v2"
+)
+
+func TestSlack_getDeployment(t *testing.T) {
+	// setup types
+	environ := []string{
+		"DEPLOYMENT_PARAMETER_REGION=us-east-1",
+		"DEPLOYMENT_PARAMETER_NOTE=say \"hi\"",
+		"DEPLOYMENT_PARAMETER_=ignored",
+		"VELA_DEPLOYMENT=production",
+	}
+
+	// setup tests
+	tests := []struct {
+		event string
+		want  Deployment
+	}{
+		{
+			event: "deployment",
+			want: Deployment{
+				Number:      3,
+				Target:      "production",
+				Task:        "",
+				Description: "Deploy v1.2.3",
+				Parameters: map[string]string{
+					"REGION": "us-east-1",
+					"NOTE":   `say \"hi\"`,
+				},
+			},
+		},
+		{
+			event: "push",
+			want:  Deployment{},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		set := flag.NewFlagSet("test", 0)
+		set.String("build-event", test.event, "")
+		set.String("build-message", "Deploy v1.2.3", "")
+		set.Int("deployment-number", 3, "")
+		set.String("deployment-target", "production", "")
+		set.String("deployment-task", "", "")
+
+		got := getDeployment(cli.NewContext(nil, set, nil), environ)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("getDeployment for %s is %+v, want %+v", test.event, got, test.want)
+		}
+	}
+}
+
+func TestSlack_Plugin_Exec_Deployment(t *testing.T) {
+	// setup types
+	var posted slack.WebhookMessage
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&posted)
+		if err != nil {
+			t.Errorf("Decode error: %v", err)
+		}
+	}))
+	defer ts.Close()
+
+	p := &Plugin{
+		Webhook: ts.URL,
+		Env: &Env{
+			Deployment: Deployment{
+				Number:     3,
+				Target:     "production",
+				Task:       "deploy",
+				Parameters: map[string]string{"REGION": "us-east-1"},
+			},
+		},
+		WebhookMsg: &slack.WebhookMessage{
+			Text: "#{{ .Deployment.Number }} {{ .Deployment.Task }} to {{ .Deployment.Target }} in {{ .Deployment.Parameters.REGION }}",
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	want := "#3 deploy to production in us-east-1"
+
+	if posted.Text != want {
+		t.Errorf("Exec posted text %s, want %s", posted.Text, want)
+	}
+}
+
+func TestSlack_Plugin_loadDeploymentTask(t *testing.T) {
+	// setup types
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/deployments/octocat/hello-world/3" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		fmt.Fprint(w, `{"number": 3, "target": "production", "task": "migrate \"db\""}`)
+	}))
+	defer ts.Close()
+
+	// setup tests
+	tests := []struct {
+		name    string
+		velaAPI bool
+		number  int
+		task    string
+		want    string
+	}{
+		{name: "vela api", velaAPI: true, number: 3, want: `migrate \"db\"`},
+		{name: "task set", velaAPI: true, number: 3, task: "deploy", want: "deploy"},
+		{name: "not a deployment", velaAPI: true, want: ""},
+		{name: "vela api disabled", number: 3, want: ""},
+		{name: "missing deployment", velaAPI: true, number: 4, want: ""},
+	}
+
+	// run tests
+	for _, test := range tests {
+		p := &Plugin{
+			VelaAPI:   test.velaAPI,
+			VelaAddr:  ts.URL,
+			VelaToken: "vela_token",
+			Env: &Env{
+				RepositoryOrg:  "octocat",
+				RepositoryName: "hello-world",
+				Deployment: Deployment{
+					Number: test.number,
+					Task:   test.task,
+				},
+			},
+		}
+
+		p.loadDeploymentTask()
+
+		if p.Env.Deployment.Task != test.want {
+			t.Errorf("loadDeploymentTask for %s is %q, want %q", test.name, p.Env.Deployment.Task, test.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// resolveRecipients resolves the Slack users for direct and ephemeral
+// messages. Direct messages are sent by replacing the message channel
+// with the direct message channel of the build author.
+func (p *Plugin) resolveRecipients(ctx context.Context, msg *slack.WebhookMessage) error {
+	api := p.client()
+
+	if p.DMAuthor {
+		user, err := p.lookupAuthor(ctx, api)
+		if err != nil {
+			return err
+		}
+
+		channel, _, _, err := api.OpenConversationContext(ctx, &slack.OpenConversationParameters{
+			Users: []string{user},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to open direct message with %s: %w", user, err)
+		}
+
+		logrus.Infof("Sending direct message to build author %s", user)
+
+		msg.Channel = channel.ID
+	}
+
+	if len(p.EphemeralUser) > 0 {
+		user, err := renderTemplate("ephemeral_user", p.EphemeralUser, p.Env)
+		if err != nil {
+			return err
+		}
+
+		// look up the user id when an email was provided
+		if strings.Contains(user, "@") {
+			u, err := api.GetUserByEmailContext(ctx, user)
+			if err != nil {
+				return fmt.Errorf("unable to find Slack user for %s: %w", user, err)
+			}
+
+			user = u.ID
+		}
+
+		p.EphemeralUser = user
+	}
+
+	return nil
+}
+
+// lookupAuthor finds the Slack user id of the build author by email,
+// falling back to the sAMAccountName from LDAP as the Slack username.
+func (p *Plugin) lookupAuthor(ctx context.Context, api *slack.Client) (string, error) {
+	if len(p.Env.BuildAuthorEmail) > 0 {
+		user, err := api.GetUserByEmailContext(ctx, p.Env.BuildAuthorEmail)
+		if err == nil {
+			return user.ID, nil
+		}
+
+		logrus.Debugf("unable to find Slack user by email %s: %v", p.Env.BuildAuthorEmail, err)
+	}
+
+	name := p.Env.BuildAuthorSAMAccountName
+	if len(name) == 0 {
+		return "", fmt.Errorf("unable to find Slack user for build author %s", p.Env.BuildAuthor)
+	}
+
+	users, err := api.GetUsersContext(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to list Slack users: %w", err)
+	}
+
+	for _, user := range users {
+		if !user.Deleted && strings.EqualFold(user.Name, name) {
+			return user.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("unable to find Slack user for build author %s", p.Env.BuildAuthor)
+}
@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+// newSlackUsersServer creates a Slack API server with users
+// for finding the build author and opening conversations.
+func newSlackUsersServer(t *testing.T, posted *string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/users.lookupByEmail", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("email") != "octocat@github.com" {
+			fmt.Fprintln(w, `{"ok": false, "error": "users_not_found"}`)
+
+			return
+		}
+
+		fmt.Fprintln(w, `{"ok": true, "user": {"id": "U0OCTOCAT", "name": "octocat"}}`)
+	})
+	mux.HandleFunc("/users.list", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, `{"ok": true, "members": [{"id": "U0DELETED", "name": "z001234", "deleted": true}, {"id": "U0Z001234", "name": "z001234"}]}`)
+	})
+	mux.HandleFunc("/conversations.open", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"ok": true, "channel": {"id": "D%s"}}`, r.FormValue("users"))
+	})
+	mux.HandleFunc("/chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
+		*posted = "message " + r.FormValue("channel")
+
+		fmt.Fprintln(w, `{"ok": true, "channel": "C024BE91L", "ts": "1503435956.000247"}`)
+	})
+	mux.HandleFunc("/chat.postEphemeral", func(w http.ResponseWriter, r *http.Request) {
+		*posted = "ephemeral " + r.FormValue("channel") + " " + r.FormValue("user")
+
+		fmt.Fprintln(w, `{"ok": true, "message_ts": "1503435956.000247"}`)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestSlack_Plugin_Exec_DM_Author(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		env  *Env
+		want string
+	}{
+		{
+			env:  &Env{BuildAuthorEmail: "octocat@github.com"},
+			want: "message DU0OCTOCAT",
+		},
+		{
+			env:  &Env{BuildAuthorEmail: "octocat@users.noreply.github.com", BuildAuthorSAMAccountName: "Z001234"},
+			want: "message DU0Z001234",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		var posted string
+
+		ts := newSlackUsersServer(t, &posted)
+
+		p := &Plugin{
+			Env:      test.env,
+			BotToken: "xoxb-token",
+			APIURL:   ts.URL + "/",
+			DMAuthor: true,
+			WebhookMsg: &slack.WebhookMessage{
+				Text: "your build failed",
+			},
+		}
+
+		err := p.Validate()
+		if err != nil {
+			t.Errorf("Validate returned err: %v", err)
+		}
+
+		err = p.Exec()
+		if err != nil {
+			t.Errorf("Exec returned err: %v", err)
+		}
+
+		if posted != test.want {
+			t.Errorf("Exec posted %q, want %q", posted, test.want)
+		}
+
+		ts.Close()
+	}
+}
+
+func TestSlack_Plugin_Exec_DM_Author_Not_Found(t *testing.T) {
+	// setup types
+	var posted string
+
+	ts := newSlackUsersServer(t, &posted)
+	defer ts.Close()
+
+	p := &Plugin{
+		Env: &Env{
+			BuildAuthor:      "hubot",
+			BuildAuthorEmail: "hubot@github.com",
+		},
+		BotToken: "xoxb-token",
+		APIURL:   ts.URL + "/",
+		DMAuthor: true,
+		WebhookMsg: &slack.WebhookMessage{
+			Text: "your build failed",
+		},
+	}
+
+	err := p.Exec()
+	if err == nil {
+		t.Error("Exec should return err due to unknown build author")
+	}
+
+	if len(posted) > 0 {
+		t.Errorf("Exec should not post a message, posted %q", posted)
+	}
+}
+
+func TestSlack_Plugin_Exec_Ephemeral_User(t *testing.T) {
+	// setup types
+	var posted string
+
+	ts := newSlackUsersServer(t, &posted)
+	defer ts.Close()
+
+	p := &Plugin{
+		Env: &Env{
+			BuildAuthorEmail: "octocat@github.com",
+		},
+		BotToken:      "xoxb-token",
+		APIURL:        ts.URL + "/",
+		EphemeralUser: "{{ .BuildAuthorEmail }}",
+		WebhookMsg: &slack.WebhookMessage{
+			Channel: "C024BE91L",
+			Text:    "your build failed",
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	if posted != "ephemeral C024BE91L U0OCTOCAT" {
+		t.Errorf("Exec posted %q", posted)
+	}
+}
+
+func TestSlack_Plugin_Validate_DM_Ephemeral(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		p    *Plugin
+	}{
+		{
+			name: "missing bot token for dm_author",
+			p:    &Plugin{Webhook: "webhook_url", DMAuthor: true},
+		},
+		{
+			name: "dm_author with ephemeral_user",
+			p:    &Plugin{BotToken: "xoxb-token", DMAuthor: true, EphemeralUser: "U0OCTOCAT"},
+		},
+		{
+			name: "ephemeral_user with post_at",
+			p:    &Plugin{BotToken: "xoxb-token", EphemeralUser: "U0OCTOCAT", PostAt: "30m"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		test.p.Env = &Env{}
+		test.p.WebhookMsg = &slack.WebhookMessage{Channel: "#builds", Text: "hello"}
+
+		err := test.p.Validate()
+		if err == nil {
+			t.Errorf("Validate should return err due to %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// Discord message size limits.
+//
+// https://discord.com/developers/docs/resources/message#embed-object-embed-limits
+const (
+	maxDiscordContent = 2000
+	maxDiscordEmbeds  = 10
+	maxDiscordFields  = 25
+)
+
+type (
+	// discord delivers messages with a Discord webhook.
+	discord struct {
+		url    string
+		client *http.Client
+	}
+
+	// discordMessage represents the payload for a Discord webhook.
+	//
+	// https://discord.com/developers/docs/resources/webhook#execute-webhook
+	discordMessage struct {
+		Content   string         `json:"content,omitempty"`
+		Username  string         `json:"username,omitempty"`
+		AvatarURL string         `json:"avatar_url,omitempty"`
+		Embeds    []discordEmbed `json:"embeds,omitempty"`
+	}
+
+	// discordEmbed represents an embed in a Discord message.
+	discordEmbed struct {
+		Title       string              `json:"title,omitempty"`
+		URL         string              `json:"url,omitempty"`
+		Description string              `json:"description,omitempty"`
+		Color       int                 `json:"color,omitempty"`
+		Timestamp   string              `json:"timestamp,omitempty"`
+		Author      *discordEmbedAuthor `json:"author,omitempty"`
+		Fields      []discordEmbedField `json:"fields,omitempty"`
+		Footer      *discordEmbedFooter `json:"footer,omitempty"`
+		Image       *discordEmbedImage  `json:"image,omitempty"`
+		Thumbnail   *discordEmbedImage  `json:"thumbnail,omitempty"`
+	}
+
+	// discordEmbedAuthor represents the author of a Discord embed.
+	discordEmbedAuthor struct {
+		Name    string `json:"name"`
+		URL     string `json:"url,omitempty"`
+		IconURL string `json:"icon_url,omitempty"`
+	}
+
+	// discordEmbedField represents a field of a Discord embed.
+	discordEmbedField struct {
+		Name   string `json:"name"`
+		Value  string `json:"value"`
+		Inline bool   `json:"inline,omitempty"`
+	}
+
+	// discordEmbedFooter represents the footer of a Discord embed.
+	discordEmbedFooter struct {
+		Text    string `json:"text"`
+		IconURL string `json:"icon_url,omitempty"`
+	}
+
+	// discordEmbedImage represents an image of a Discord embed.
+	discordEmbedImage struct {
+		URL string `json:"url"`
+	}
+)
+
+// Notify posts the message to the Discord webhook.
+func (n *discord) Notify(ctx context.Context, msg *slack.WebhookMessage) (string, string, error) {
+	logrus.Info("Posting Discord webhook message...")
+
+	err := postJSON(ctx, n.client, n.url, toDiscord(msg))
+	if err != nil {
+		return "", "", fmt.Errorf("unable to post Discord webhook message: %w", err)
+	}
+
+	return "", "", nil
+}
+
+// toDiscord converts the Slack message into a Discord
+// message with an embed for each attachment.
+func toDiscord(msg *slack.WebhookMessage) *discordMessage {
+	m := &discordMessage{
+		Content:   truncateText(markdownLinks(msg.Text), maxDiscordContent, ""),
+		Username:  msg.Username,
+		AvatarURL: msg.IconURL,
+	}
+
+	for _, a := range msg.Attachments {
+		if len(m.Embeds) == maxDiscordEmbeds {
+			break
+		}
+
+		e := discordEmbed{
+			Title:       a.Title,
+			URL:         a.TitleLink,
+			Description: markdownLinks(joinLines(a.Pretext, a.Text)),
+			Color:       intColor(a.Color),
+		}
+
+		if ts, err := a.Ts.Int64(); err == nil && ts > 0 {
+			e.Timestamp = time.Unix(ts, 0).UTC().Format(time.RFC3339)
+		}
+
+		if len(a.AuthorName) > 0 {
+			e.Author = &discordEmbedAuthor{Name: a.AuthorName, URL: a.AuthorLink, IconURL: a.AuthorIcon}
+		}
+
+		for _, f := range a.Fields {
+			if len(e.Fields) == maxDiscordFields {
+				break
+			}
+
+			e.Fields = append(e.Fields, discordEmbedField{Name: f.Title, Value: markdownLinks(f.Value), Inline: f.Short})
+		}
+
+		if len(a.Footer) > 0 {
+			e.Footer = &discordEmbedFooter{Text: a.Footer, IconURL: a.FooterIcon}
+		}
+
+		if len(a.ImageURL) > 0 {
+			e.Image = &discordEmbedImage{URL: a.ImageURL}
+		}
+
+		if len(a.ThumbURL) > 0 {
+			e.Thumbnail = &discordEmbedImage{URL: a.ThumbURL}
+		}
+
+		m.Embeds = append(m.Embeds, e)
+	}
+
+	return m
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_toDiscord(t *testing.T) {
+	// setup types
+	msg := &slack.WebhookMessage{
+		Username: "vela",
+		IconURL:  "https://vela.example.com/icon.png",
+		Text:     "build <https://vela.example.com/1|#1> finished",
+		Attachments: []slack.Attachment{
+			{
+				Color:      "good",
+				AuthorName: "octocat",
+				Title:      "octocat/hello-world",
+				TitleLink:  "https://github.com/octocat/hello-world",
+				Text:       "Build Message: Update README",
+				Fields:     []slack.AttachmentField{{Title: "Priority", Value: "High", Short: true}},
+				Footer:     "Vela",
+				Ts:         "1563474076",
+			},
+		},
+	}
+
+	want := &discordMessage{
+		Content:   "build [#1](https://vela.example.com/1) finished",
+		Username:  "vela",
+		AvatarURL: "https://vela.example.com/icon.png",
+		Embeds: []discordEmbed{
+			{
+				Title:       "octocat/hello-world",
+				URL:         "https://github.com/octocat/hello-world",
+				Description: "Build Message: Update README",
+				Color:       0x2eb886,
+				Timestamp:   "2019-07-18T18:21:16Z",
+				Author:      &discordEmbedAuthor{Name: "octocat"},
+				Fields:      []discordEmbedField{{Name: "Priority", Value: "High", Inline: true}},
+				Footer:      &discordEmbedFooter{Text: "Vela"},
+			},
+		},
+	}
+
+	got := toDiscord(msg)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("toDiscord is %+v, want %+v", got, want)
+	}
+}
+
+func TestSlack_toDiscord_Limits(t *testing.T) {
+	// setup types
+	msg := &slack.WebhookMessage{
+		Text:        strings.Repeat("a", maxDiscordContent+1),
+		Attachments: make([]slack.Attachment, maxDiscordEmbeds+1),
+	}
+
+	got := toDiscord(msg)
+
+	if len([]rune(got.Content)) > maxDiscordContent {
+		t.Errorf("toDiscord content length is %d", len([]rune(got.Content)))
+	}
+
+	if len(got.Embeds) != maxDiscordEmbeds {
+		t.Errorf("toDiscord embeds is %d, want %d", len(got.Embeds), maxDiscordEmbeds)
+	}
+}
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// errorClass represents the cause of a plugin failure.
+type errorClass string
+
+// classes of plugin failures.
+const (
+	classConfig   errorClass = "config"
+	classTemplate errorClass = "template"
+	classRemote   errorClass = "remote"
+	classDelivery errorClass = "delivery"
+)
+
+// exit codes for the classes of plugin failures.
+const (
+	exitUnknown  = 1
+	exitConfig   = 2
+	exitTemplate = 3
+	exitRemote   = 4
+	exitDelivery = 5
+)
+
+type (
+	// classError represents an error with the class of the failure.
+	classError struct {
+		class errorClass
+		err   error
+	}
+
+	// result represents the outcome of the plugin written to the result file.
+	result struct {
+		Status     string       `json:"status"`
+		Provider   string       `json:"provider,omitempty"`
+		Mode       string       `json:"mode,omitempty"`
+		Targets    []messageRef `json:"targets"`
+		ErrorClass string       `json:"error_class,omitempty"`
+		Error      string       `json:"error,omitempty"`
+		Reason     string       `json:"reason,omitempty"`
+		ExitCode   int          `json:"exit_code"`
+		Continued  bool         `json:"continued,omitempty"`
+	}
+)
+
+// Error returns the message of the wrapped error.
+func (e *classError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *classError) Unwrap() error {
+	return e.err
+}
+
+// classify adds the class to the error unless
+// the error was already classified.
+func classify(class errorClass, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var ce *classError
+	if errors.As(err, &ce) {
+		return err
+	}
+
+	return &classError{class: class, err: err}
+}
+
+// classOf returns the class of the error.
+func classOf(err error) errorClass {
+	var ce *classError
+	if errors.As(err, &ce) {
+		return ce.class
+	}
+
+	return ""
+}
+
+// exitCode returns the process exit code for the error.
+func exitCode(err error) int {
+	switch classOf(err) {
+	case classConfig:
+		return exitConfig
+	case classTemplate:
+		return exitTemplate
+	case classRemote:
+		return exitRemote
+	case classDelivery:
+		return exitDelivery
+	default:
+		return exitUnknown
+	}
+}
+
+// continueOnError checks if the error shouldn't fail the build. Template
+// errors still fail unless disabled since they need to be fixed in the
+// pipeline while configuration errors always fail.
+func (p *Plugin) continueOnError(err error) bool {
+	if err == nil || !p.ContinueOnError {
+		return false
+	}
+
+	switch classOf(err) {
+	case classConfig:
+		return false
+	case classTemplate:
+		return !p.FailOnTemplateError
+	default:
+		return true
+	}
+}
+
+// writeResult writes the outcome of the plugin to the result file
+// so wrapper pipelines can decide whether to retry or ignore it.
+func (p *Plugin) writeResult(refs []messageRef, err error, continued bool) {
+	if len(p.ResultFile) == 0 {
+		return
+	}
+
+	res := &result{
+		Status:   "success",
+		Provider: p.Provider,
+		Mode:     p.Mode,
+		Targets:  []messageRef{},
+	}
+
+	res.Targets = append(res.Targets, refs...)
+
+	switch {
+	case errors.Is(err, errSkipped):
+		res.Status = "skipped"
+		res.Reason = err.Error()
+	case err != nil:
+		res.Status = "failure"
+		res.ErrorClass = string(classOf(err))
+		res.Error = p.redact(err.Error())
+		res.ExitCode = exitCode(err)
+	}
+
+	if continued {
+		res.ExitCode = 0
+		res.Continued = true
+	}
+
+	data, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		logrus.Warnf("unable to marshal result: %v", err)
+
+		return
+	}
+
+	path := p.ResultFile
+	if p.Env != nil {
+		path = workspacePath(p.Env.BuildWorkspace, path)
+	}
+
+	err = os.WriteFile(path, data, 0o600)
+	if err != nil {
+		logrus.Warnf("unable to write result file %s: %v", path, err)
+	}
+}
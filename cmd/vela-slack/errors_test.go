@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_exitCode(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{err: errors.New("unknown"), want: exitUnknown},
+		{err: classify(classConfig, errors.New("config")), want: exitConfig},
+		{err: classify(classTemplate, errors.New("template")), want: exitTemplate},
+		{err: classify(classRemote, errors.New("remote")), want: exitRemote},
+		{err: classify(classDelivery, errors.New("delivery")), want: exitDelivery},
+		{err: fmt.Errorf("wrapped: %w", classify(classRemote, errors.New("remote"))), want: exitRemote},
+		{err: classify(classDelivery, classify(classTemplate, errors.New("template"))), want: exitTemplate},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := exitCode(test.err)
+
+		if got != test.want {
+			t.Errorf("exitCode for %v is %d, want %d", test.err, got, test.want)
+		}
+	}
+}
+
+func TestSlack_Plugin_Exec_ResultFile(t *testing.T) {
+	// setup types
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			http.Error(w, "no_service", http.StatusNotFound)
+
+			return
+		}
+
+		fmt.Fprintln(w, "ok")
+	}))
+	defer ts.Close()
+
+	// setup tests
+	tests := []struct {
+		name    string
+		webhook string
+		text    string
+		want    result
+	}{
+		{
+			name:    "success",
+			webhook: ts.URL,
+			text:    "hello",
+			want:    result{Status: "success", Targets: []messageRef{{}}},
+		},
+		{
+			name:    "template error",
+			webhook: ts.URL,
+			text:    "{{ .Missing }}",
+			want:    result{Status: "failure", Targets: []messageRef{}, ErrorClass: "template", ExitCode: exitTemplate},
+		},
+		{
+			name:    "delivery error",
+			webhook: ts.URL + "/fail",
+			text:    "hello",
+			want:    result{Status: "failure", Targets: []messageRef{}, ErrorClass: "delivery", ExitCode: exitDelivery},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		workspace := t.TempDir()
+
+		p := &Plugin{
+			Webhook:    test.webhook,
+			ResultFile: "result.json",
+			Env:        &Env{BuildWorkspace: workspace},
+			WebhookMsg: &slack.WebhookMessage{
+				Text: test.text,
+			},
+		}
+
+		err := p.Exec()
+
+		if test.want.Status == "success" && err != nil {
+			t.Errorf("Exec returned err for %s: %v", test.name, err)
+		}
+
+		if test.want.Status == "failure" && err == nil {
+			t.Errorf("Exec should have returned err for %s", test.name)
+		}
+
+		data, err := os.ReadFile(filepath.Join(workspace, "result.json"))
+		if err != nil {
+			t.Errorf("ReadFile returned err for %s: %v", test.name, err)
+		}
+
+		var got result
+
+		err = json.Unmarshal(data, &got)
+		if err != nil {
+			t.Errorf("Unmarshal returned err for %s: %v", test.name, err)
+		}
+
+		if got.Status != test.want.Status || got.ErrorClass != test.want.ErrorClass ||
+			got.ExitCode != test.want.ExitCode || len(got.Targets) != len(test.want.Targets) {
+			t.Errorf("result for %s is %+v, want %+v", test.name, got, test.want)
+		}
+
+		if test.want.Status == "failure" && len(got.Error) == 0 {
+			t.Errorf("result for %s is missing the error", test.name)
+		}
+	}
+}
+
+func TestSlack_Plugin_Exec_ContinueOnError(t *testing.T) {
+	// setup types
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	// setup tests
+	tests := []struct {
+		name                string
+		text                string
+		continueOnError     bool
+		failOnTemplateError bool
+		failure             bool
+	}{
+		{
+			name:    "delivery error",
+			text:    "hello",
+			failure: true,
+		},
+		{
+			name:            "delivery error with continue_on_error",
+			text:            "hello",
+			continueOnError: true,
+		},
+		{
+			name:                "template error with continue_on_error",
+			text:                "{{ .Missing }}",
+			continueOnError:     true,
+			failOnTemplateError: true,
+			failure:             true,
+		},
+		{
+			name:            "template error without fail_on_template_error",
+			text:            "{{ .Missing }}",
+			continueOnError: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		workspace := t.TempDir()
+
+		p := &Plugin{
+			Webhook:             ts.URL,
+			ContinueOnError:     test.continueOnError,
+			FailOnTemplateError: test.failOnTemplateError,
+			ResultFile:          "result.json",
+			Env:                 &Env{BuildWorkspace: workspace},
+			WebhookMsg: &slack.WebhookMessage{
+				Text: test.text,
+			},
+		}
+
+		err := p.Exec()
+
+		if test.failure && err == nil {
+			t.Errorf("Exec should have returned err for %s", test.name)
+		}
+
+		if !test.failure && err != nil {
+			t.Errorf("Exec returned err for %s: %v", test.name, err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(workspace, "result.json"))
+		if err != nil {
+			t.Errorf("ReadFile returned err for %s: %v", test.name, err)
+		}
+
+		var got result
+
+		err = json.Unmarshal(data, &got)
+		if err != nil {
+			t.Errorf("Unmarshal returned err for %s: %v", test.name, err)
+		}
+
+		if got.Status != "failure" || got.Continued == test.failure || (got.ExitCode == 0) == test.failure {
+			t.Errorf("result for %s is %+v", test.name, got)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+type (
+	// googleChat delivers messages with a Google Chat webhook.
+	googleChat struct {
+		url    string
+		client *http.Client
+	}
+
+	// googleChatMessage represents the payload for a Google Chat webhook.
+	//
+	// https://developers.google.com/workspace/chat/api/reference/rest/v1/spaces.messages
+	googleChatMessage struct {
+		Text    string           `json:"text,omitempty"`
+		CardsV2 []googleChatCard `json:"cardsV2,omitempty"`
+	}
+
+	// googleChatCard represents a card in a Google Chat message.
+	googleChatCard struct {
+		CardID string             `json:"cardId"`
+		Card   googleChatCardBody `json:"card"`
+	}
+
+	// googleChatCardBody represents the content of a Google Chat card.
+	googleChatCardBody struct {
+		Header   *googleChatHeader   `json:"header,omitempty"`
+		Sections []googleChatSection `json:"sections"`
+	}
+
+	// googleChatHeader represents the header of a Google Chat card.
+	googleChatHeader struct {
+		Title    string `json:"title"`
+		Subtitle string `json:"subtitle,omitempty"`
+		ImageURL string `json:"imageUrl,omitempty"`
+	}
+
+	// googleChatSection represents a section of a Google Chat card.
+	googleChatSection struct {
+		Widgets []googleChatWidget `json:"widgets"`
+	}
+
+	// googleChatWidget represents a widget in a Google Chat card section.
+	googleChatWidget struct {
+		TextParagraph *googleChatText       `json:"textParagraph,omitempty"`
+		DecoratedText *googleChatDecorated  `json:"decoratedText,omitempty"`
+		Image         *googleChatImage      `json:"image,omitempty"`
+		ButtonList    *googleChatButtonList `json:"buttonList,omitempty"`
+	}
+
+	// googleChatText represents a text paragraph widget.
+	googleChatText struct {
+		Text string `json:"text"`
+	}
+
+	// googleChatDecorated represents a decorated text widget.
+	googleChatDecorated struct {
+		TopLabel string `json:"topLabel,omitempty"`
+		Text     string `json:"text"`
+		WrapText bool   `json:"wrapText"`
+	}
+
+	// googleChatImage represents an image widget.
+	googleChatImage struct {
+		ImageURL string `json:"imageUrl"`
+	}
+
+	// googleChatButtonList represents a list of buttons widget.
+	googleChatButtonList struct {
+		Buttons []googleChatButton `json:"buttons"`
+	}
+
+	// googleChatButton represents a button opening a link.
+	googleChatButton struct {
+		Text    string            `json:"text"`
+		OnClick googleChatOnClick `json:"onClick"`
+	}
+
+	// googleChatOnClick represents the action of a button.
+	googleChatOnClick struct {
+		OpenLink googleChatLink `json:"openLink"`
+	}
+
+	// googleChatLink represents a link opened by a button.
+	googleChatLink struct {
+		URL string `json:"url"`
+	}
+)
+
+// Notify posts the message to the Google Chat webhook.
+func (n *googleChat) Notify(ctx context.Context, msg *slack.WebhookMessage) (string, string, error) {
+	logrus.Info("Posting Google Chat webhook message...")
+
+	err := postJSON(ctx, n.client, n.url, toGoogleChat(msg))
+	if err != nil {
+		return "", "", fmt.Errorf("unable to post Google Chat webhook message: %w", err)
+	}
+
+	return "", "", nil
+}
+
+// toGoogleChat converts the Slack message into a Google Chat
+// message with a card for each attachment.
+//
+// Google Chat text supports the Slack <url|text> link format
+// while card text uses HTML formatting.
+func toGoogleChat(msg *slack.WebhookMessage) *googleChatMessage {
+	m := &googleChatMessage{
+		Text: msg.Text,
+	}
+
+	for i, a := range msg.Attachments {
+		var widgets []googleChatWidget
+
+		if text := joinLines(a.Pretext, a.Text); len(text) > 0 {
+			widgets = append(widgets, googleChatWidget{TextParagraph: &googleChatText{Text: htmlLinks(text)}})
+		}
+
+		for _, f := range a.Fields {
+			widgets = append(widgets, googleChatWidget{
+				DecoratedText: &googleChatDecorated{TopLabel: f.Title, Text: htmlLinks(f.Value), WrapText: true},
+			})
+		}
+
+		if len(a.ImageURL) > 0 {
+			widgets = append(widgets, googleChatWidget{Image: &googleChatImage{ImageURL: a.ImageURL}})
+		}
+
+		if len(a.TitleLink) > 0 {
+			widgets = append(widgets, googleChatWidget{ButtonList: &googleChatButtonList{
+				Buttons: []googleChatButton{{Text: "Open", OnClick: googleChatOnClick{OpenLink: googleChatLink{URL: a.TitleLink}}}},
+			}})
+		}
+
+		if len(a.Footer) > 0 {
+			widgets = append(widgets, googleChatWidget{TextParagraph: &googleChatText{Text: htmlLinks(a.Footer)}})
+		}
+
+		card := googleChatCard{
+			CardID: fmt.Sprintf("attachment-%d", i),
+			Card:   googleChatCardBody{Sections: []googleChatSection{{Widgets: widgets}}},
+		}
+
+		switch {
+		case len(a.Title) > 0:
+			card.Card.Header = &googleChatHeader{Title: a.Title, Subtitle: a.AuthorName, ImageURL: a.ThumbURL}
+		case len(a.AuthorName) > 0:
+			card.Card.Header = &googleChatHeader{Title: a.AuthorName, ImageURL: a.AuthorIcon}
+		}
+
+		// cards require at least one widget
+		if len(widgets) == 0 {
+			card.Card.Sections[0].Widgets = []googleChatWidget{{TextParagraph: &googleChatText{Text: html.EscapeString(a.Fallback)}}}
+		}
+
+		m.CardsV2 = append(m.CardsV2, card)
+	}
+
+	return m
+}
+
+// htmlLinks converts Slack formatted links into HTML links.
+func htmlLinks(s string) string {
+	return slackLinkRegex.ReplaceAllStringFunc(s, func(m string) string {
+		parts := slackLinkRegex.FindStringSubmatch(m)
+
+		text := parts[2]
+		if len(text) == 0 {
+			text = parts[1]
+		}
+
+		return fmt.Sprintf(`<a href="%s">%s</a>`, parts[1], text)
+	})
+}
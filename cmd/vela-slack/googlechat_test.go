@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_toGoogleChat(t *testing.T) {
+	// setup types
+	msg := &slack.WebhookMessage{
+		Text: "build <https://vela.example.com/1|#1> finished",
+		Attachments: []slack.Attachment{
+			{
+				AuthorName: "octocat",
+				Title:      "octocat/hello-world",
+				TitleLink:  "https://github.com/octocat/hello-world",
+				Text:       "see <https://vela.example.com/1|logs>",
+				Fields:     []slack.AttachmentField{{Title: "Priority", Value: "High"}},
+			},
+			{
+				Fallback: "empty",
+			},
+		},
+	}
+
+	want := `{"text":"build <https://vela.example.com/1|#1> finished","cardsV2":[` +
+		`{"cardId":"attachment-0","card":{"header":{"title":"octocat/hello-world","subtitle":"octocat"},"sections":[{"widgets":[` +
+		`{"textParagraph":{"text":"see <a href=\"https://vela.example.com/1\">logs</a>"}},` +
+		`{"decoratedText":{"topLabel":"Priority","text":"High","wrapText":true}},` +
+		`{"buttonList":{"buttons":[{"text":"Open","onClick":{"openLink":{"url":"https://github.com/octocat/hello-world"}}}]}}]}]}},` +
+		`{"cardId":"attachment-1","card":{"sections":[{"widgets":[{"textParagraph":{"text":"empty"}}]}]}}]}`
+
+	got, err := json.Marshal(toGoogleChat(msg))
+	if err != nil {
+		t.Errorf("Marshal returned err: %v", err)
+	}
+
+	// compare the decoded values since the encoder escapes HTML characters
+	var gotValue, wantValue interface{}
+
+	_ = json.Unmarshal(got, &gotValue)
+	_ = json.Unmarshal([]byte(want), &wantValue)
+
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("toGoogleChat is %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// newHTTPClient creates the client used for all outbound HTTP requests.
+//
+// The proxy falls back to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+// environment variables and the certificates in the CA file are
+// trusted in addition to the system certificates.
+func newHTTPClient(timeout time.Duration, proxy, caFile string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if len(proxy) > 0 {
+		u, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy provided: %w", err)
+		}
+
+		transport.Proxy = http.ProxyURL(u)
+	}
+
+	if len(caFile) > 0 {
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+
+		caCerts, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read ssl cert file: %w", err)
+		}
+
+		if !roots.AppendCertsFromPEM(caCerts) {
+			return nil, fmt.Errorf("no certificates found in ssl cert file %s", caFile)
+		}
+
+		transport.TLSClientConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    roots,
+		}
+	}
+
+	return &http.Client{Timeout: timeout, Transport: transport}, nil
+}
+
+// httpClient returns the client for outbound HTTP requests.
+func (p *Plugin) httpClient() *http.Client {
+	if p.HTTPClient == nil {
+		return http.DefaultClient
+	}
+
+	return p.HTTPClient
+}
@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_newHTTPClient_Proxy(t *testing.T) {
+	// setup types
+	var requested string
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.String()
+
+		fmt.Fprintln(w, "ok")
+	}))
+	defer proxy.Close()
+
+	client, err := newHTTPClient(time.Second, proxy.URL, "")
+	if err != nil {
+		t.Errorf("newHTTPClient returned err: %v", err)
+	}
+
+	p := &Plugin{
+		Webhook:    "http://slack.example.com/services/hook",
+		Env:        &Env{},
+		HTTPClient: client,
+		WebhookMsg: &slack.WebhookMessage{
+			Text: "hello",
+		},
+	}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	if requested != p.Webhook {
+		t.Errorf("proxy received request for %s, want %s", requested, p.Webhook)
+	}
+}
+
+func TestSlack_newHTTPClient_CACert(t *testing.T) {
+	// setup types
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, "ok")
+	}))
+	defer ts.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+
+	err := os.WriteFile(caFile, cert, 0o600)
+	if err != nil {
+		t.Errorf("WriteFile returned err: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		caFile  string
+		failure bool
+	}{
+		{caFile: caFile, failure: false},
+		{caFile: "", failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		client, err := newHTTPClient(time.Second, "", test.caFile)
+		if err != nil {
+			t.Errorf("newHTTPClient returned err: %v", err)
+		}
+
+		p := &Plugin{
+			Webhook:    ts.URL,
+			Env:        &Env{},
+			HTTPClient: client,
+			WebhookMsg: &slack.WebhookMessage{
+				Text: "hello",
+			},
+		}
+
+		err = p.Exec()
+
+		if test.failure && err == nil {
+			t.Errorf("Exec should have returned err for CA file %q", test.caFile)
+		}
+
+		if !test.failure && err != nil {
+			t.Errorf("Exec returned err for CA file %q: %v", test.caFile, err)
+		}
+	}
+}
+
+func TestSlack_newHTTPClient_Timeout(t *testing.T) {
+	// setup types
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+
+		fmt.Fprintln(w, "ok")
+	}))
+	defer ts.Close()
+
+	client, err := newHTTPClient(50*time.Millisecond, "", "")
+	if err != nil {
+		t.Errorf("newHTTPClient returned err: %v", err)
+	}
+
+	p := &Plugin{
+		Webhook:    ts.URL,
+		Env:        &Env{},
+		HTTPClient: client,
+		WebhookMsg: &slack.WebhookMessage{
+			Text: "hello",
+		},
+	}
+
+	err = p.Exec()
+	if err == nil {
+		t.Error("Exec should return err due to timeout")
+	}
+}
+
+func TestSlack_newHTTPClient_Failure(t *testing.T) {
+	// setup types
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+
+	err := os.WriteFile(empty, []byte("not a certificate"), 0o600)
+	if err != nil {
+		t.Errorf("WriteFile returned err: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name   string
+		proxy  string
+		caFile string
+	}{
+		{name: "invalid proxy", proxy: "://proxy"},
+		{name: "missing CA file", caFile: "testdata/missing.pem"},
+		{name: "CA file without certificates", caFile: empty},
+	}
+
+	// run tests
+	for _, test := range tests {
+		_, err := newHTTPClient(time.Second, test.proxy, test.caFile)
+		if err == nil {
+			t.Errorf("newHTTPClient should have returned err for %s", test.name)
+		}
+	}
+}
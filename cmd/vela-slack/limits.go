@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// Slack message size limits.
+//
+// https://api.slack.com/methods/chat.postMessage#truncating
+// https://api.slack.com/reference/block-kit/blocks
+const (
+	maxTextLength    = 40000
+	maxBlocks        = 50
+	maxAttachments   = 100
+	maxSectionText   = 3000
+	maxSectionFields = 10
+	maxFieldText     = 2000
+	maxHeaderText    = 150
+)
+
+// overflow options for messages exceeding the Slack limits.
+const (
+	overflowTruncate = "truncate"
+	overflowSplit    = "split"
+)
+
+// enforceLimits truncates the message so it fits within the Slack
+// size limits. The link is appended to truncated text so the reader
+// can find the full content.
+func enforceLimits(msg *slack.WebhookMessage, link string) {
+	msg.Text = truncateText(msg.Text, maxTextLength, link)
+
+	if len(msg.Attachments) > maxAttachments {
+		msg.Attachments = msg.Attachments[:maxAttachments]
+	}
+
+	for i := range msg.Attachments {
+		attachment := &msg.Attachments[i]
+
+		attachment.Pretext = truncateText(attachment.Pretext, maxTextLength, link)
+		attachment.Text = truncateText(attachment.Text, maxTextLength, link)
+
+		for j := range attachment.Fields {
+			attachment.Fields[j].Value = truncateText(attachment.Fields[j].Value, maxFieldText, link)
+		}
+	}
+
+	if msg.Blocks == nil {
+		return
+	}
+
+	if len(msg.Blocks.BlockSet) > maxBlocks {
+		msg.Blocks.BlockSet = msg.Blocks.BlockSet[:maxBlocks]
+	}
+
+	for _, block := range msg.Blocks.BlockSet {
+		switch b := block.(type) {
+		case *slack.SectionBlock:
+			truncateTextObject(b.Text, maxSectionText, link)
+
+			if len(b.Fields) > maxSectionFields {
+				b.Fields = b.Fields[:maxSectionFields]
+			}
+
+			for _, field := range b.Fields {
+				truncateTextObject(field, maxFieldText, link)
+			}
+		case *slack.HeaderBlock:
+			// header blocks only support plain text
+			truncateTextObject(b.Text, maxHeaderText, "")
+		}
+	}
+}
+
+// splitMessage splits the message into a list of messages that each
+// fit within the Slack size limits. The first message keeps all of
+// the original settings while the follow-up messages only carry the
+// remaining text and blocks.
+func splitMessage(msg *slack.WebhookMessage) []*slack.WebhookMessage {
+	texts := splitText(msg.Text, maxTextLength)
+
+	var blocks [][]slack.Block
+
+	if msg.Blocks != nil {
+		for i := 0; i < len(msg.Blocks.BlockSet); i += maxBlocks {
+			end := min(i+maxBlocks, len(msg.Blocks.BlockSet))
+
+			blocks = append(blocks, msg.Blocks.BlockSet[i:end])
+		}
+	}
+
+	first := *msg
+	first.Text = ""
+	first.Blocks = nil
+
+	msgs := []*slack.WebhookMessage{&first}
+
+	for i := 0; i < max(len(texts), len(blocks)); i++ {
+		m := &first
+
+		if i > 0 {
+			m = &slack.WebhookMessage{
+				Username:  msg.Username,
+				IconEmoji: msg.IconEmoji,
+				IconURL:   msg.IconURL,
+				Channel:   msg.Channel,
+				Parse:     msg.Parse,
+			}
+
+			msgs = append(msgs, m)
+		}
+
+		if i < len(texts) {
+			m.Text = texts[i]
+		}
+
+		if i < len(blocks) {
+			m.Blocks = &slack.Blocks{BlockSet: blocks[i]}
+		}
+	}
+
+	return msgs
+}
+
+// truncateText shortens the text to the limit, adding an
+// ellipsis and a link to the full content when provided.
+func truncateText(text string, limit int, link string) string {
+	r := []rune(text)
+	if len(r) <= limit {
+		return text
+	}
+
+	suffix := "…"
+	if len(link) > 0 {
+		suffix = fmt.Sprintf("… <%s|view full message>", link)
+	}
+
+	cut := max(limit-len([]rune(suffix)), 0)
+
+	return string(r[:cut]) + suffix
+}
+
+// truncateTextObject shortens the text of the block
+// text object to the limit when one is provided.
+func truncateTextObject(obj *slack.TextBlockObject, limit int, link string) {
+	if obj == nil {
+		return
+	}
+
+	// links are only rendered for mrkdwn text
+	if obj.Type != slack.MarkdownType {
+		link = ""
+	}
+
+	obj.Text = truncateText(obj.Text, limit, link)
+}
+
+// splitText splits the text into chunks within the limit,
+// preferring to break on a newline when possible.
+func splitText(text string, limit int) []string {
+	var chunks []string
+
+	r := []rune(text)
+
+	for len(r) > limit {
+		cut := limit
+
+		// break after the last newline within the chunk
+		idx := strings.LastIndex(string(r[:limit]), "\n")
+		if idx > 0 {
+			cut = len([]rune(string(r[:limit])[:idx])) + 1
+		}
+
+		chunks = append(chunks, string(r[:cut]))
+		r = r[cut:]
+	}
+
+	if len(r) > 0 {
+		chunks = append(chunks, string(r))
+	}
+
+	return chunks
+}
@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_truncateText(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		text  string
+		limit int
+		link  string
+		want  string
+	}{
+		{text: "short", limit: 10, want: "short"},
+		{text: "longer message", limit: 7, want: "longer…"},
+		{text: strings.Repeat("a", 40), limit: 35, link: "https://vela", want: "a… <https://vela|view full message>"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := truncateText(test.text, test.limit, test.link)
+
+		if got != test.want {
+			t.Errorf("truncateText is %s, want %s", got, test.want)
+		}
+
+		if len([]rune(got)) > test.limit {
+			t.Errorf("truncateText length is %d, want at most %d", len([]rune(got)), test.limit)
+		}
+	}
+}
+
+func TestSlack_splitText(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		text  string
+		limit int
+		want  []string
+	}{
+		{text: "", limit: 5, want: nil},
+		{text: "short", limit: 5, want: []string{"short"}},
+		{text: "abcdefgh", limit: 3, want: []string{"abc", "def", "gh"}},
+		{text: "ab\ncdef\ngh", limit: 6, want: []string{"ab\n", "cdef\n", "gh"}},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := splitText(test.text, test.limit)
+
+		if strings.Join(got, "|") != strings.Join(test.want, "|") || len(got) != len(test.want) {
+			t.Errorf("splitText is %q, want %q", got, test.want)
+		}
+	}
+}
+
+func TestSlack_enforceLimits(t *testing.T) {
+	// setup types
+	fields := make([]*slack.TextBlockObject, 12)
+	for i := range fields {
+		fields[i] = slack.NewTextBlockObject(slack.MarkdownType, strings.Repeat("f", maxFieldText+1), false, false)
+	}
+
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, strings.Repeat("h", maxHeaderText+1), false, false)),
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, strings.Repeat("s", maxSectionText+1), false, false), fields, nil),
+	}
+
+	for len(blocks) < maxBlocks+5 {
+		blocks = append(blocks, slack.NewDividerBlock())
+	}
+
+	attachments := make([]slack.Attachment, maxAttachments+1)
+	for i := range attachments {
+		attachments[i] = slack.Attachment{
+			Pretext: strings.Repeat("p", maxTextLength+1),
+			Text:    strings.Repeat("a", maxTextLength+1),
+			Fields: []slack.AttachmentField{
+				{Title: "Field", Value: strings.Repeat("v", maxFieldText+1)},
+			},
+		}
+	}
+
+	msg := &slack.WebhookMessage{
+		Text:        strings.Repeat("t", maxTextLength+1),
+		Attachments: attachments,
+		Blocks:      &slack.Blocks{BlockSet: blocks},
+	}
+
+	enforceLimits(msg, "https://vela")
+
+	if len([]rune(msg.Text)) > maxTextLength {
+		t.Errorf("enforceLimits text length is %d", len([]rune(msg.Text)))
+	}
+
+	if len(msg.Attachments) != maxAttachments {
+		t.Errorf("enforceLimits attachments is %d, want %d", len(msg.Attachments), maxAttachments)
+	}
+
+	for _, attachment := range msg.Attachments {
+		if len([]rune(attachment.Pretext)) > maxTextLength || len([]rune(attachment.Text)) > maxTextLength {
+			t.Errorf("enforceLimits attachment text lengths are %d and %d", len([]rune(attachment.Pretext)), len([]rune(attachment.Text)))
+		}
+
+		if !strings.HasSuffix(attachment.Text, "<https://vela|view full message>") {
+			t.Errorf("enforceLimits attachment text is missing the link")
+		}
+
+		if len([]rune(attachment.Fields[0].Value)) > maxFieldText {
+			t.Errorf("enforceLimits attachment field length is %d", len([]rune(attachment.Fields[0].Value)))
+		}
+	}
+
+	if len(msg.Blocks.BlockSet) != maxBlocks {
+		t.Errorf("enforceLimits blocks is %d, want %d", len(msg.Blocks.BlockSet), maxBlocks)
+	}
+
+	header := msg.Blocks.BlockSet[0].(*slack.HeaderBlock)
+	if len([]rune(header.Text.Text)) > maxHeaderText || strings.Contains(header.Text.Text, "https://vela") {
+		t.Errorf("enforceLimits header is %s", header.Text.Text)
+	}
+
+	section := msg.Blocks.BlockSet[1].(*slack.SectionBlock)
+	if len([]rune(section.Text.Text)) > maxSectionText {
+		t.Errorf("enforceLimits section text length is %d", len([]rune(section.Text.Text)))
+	}
+
+	if len(section.Fields) != maxSectionFields {
+		t.Errorf("enforceLimits section fields is %d, want %d", len(section.Fields), maxSectionFields)
+	}
+
+	for _, field := range section.Fields {
+		if len([]rune(field.Text)) > maxFieldText {
+			t.Errorf("enforceLimits field length is %d", len([]rune(field.Text)))
+		}
+	}
+}
+
+func TestSlack_splitMessage(t *testing.T) {
+	// setup types
+	blocks := make([]slack.Block, maxBlocks+1)
+	for i := range blocks {
+		blocks[i] = slack.NewDividerBlock()
+	}
+
+	msg := &slack.WebhookMessage{
+		Channel:     "#builds",
+		Text:        strings.Repeat("t", maxTextLength*2+1),
+		Attachments: []slack.Attachment{{Text: "attachment"}},
+		Blocks:      &slack.Blocks{BlockSet: blocks},
+	}
+
+	got := splitMessage(msg)
+
+	if len(got) != 3 {
+		t.Fatalf("splitMessage returned %d messages, want 3", len(got))
+	}
+
+	if len(got[0].Attachments) != 1 || len(got[1].Attachments) != 0 {
+		t.Error("splitMessage should only keep attachments on the first message")
+	}
+
+	if len(got[0].Blocks.BlockSet) != maxBlocks || len(got[1].Blocks.BlockSet) != 1 || got[2].Blocks != nil {
+		t.Error("splitMessage should split blocks across messages")
+	}
+
+	for _, m := range got {
+		if m.Channel != "#builds" {
+			t.Errorf("splitMessage channel is %s, want #builds", m.Channel)
+		}
+
+		if len([]rune(m.Text)) > maxTextLength {
+			t.Errorf("splitMessage text length is %d", len([]rune(m.Text)))
+		}
+	}
+}
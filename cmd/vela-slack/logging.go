@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// formats for the plugin logs.
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// fieldsHook is a logrus hook adding the build fields to every log entry.
+type fieldsHook struct {
+	fields logrus.Fields
+}
+
+// setLogFormat sets the formatter for the plugin logs.
+func setLogFormat(format string) error {
+	switch format {
+	case "", logFormatText:
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	case logFormatJSON:
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		return fmt.Errorf("invalid log format provided: %s", format)
+	}
+
+	return nil
+}
+
+// Levels returns the log levels the hook fires for.
+func (h *fieldsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the build fields to the entry without
+// replacing fields already set on the entry.
+func (h *fieldsHook) Fire(entry *logrus.Entry) error {
+	for key, value := range h.fields {
+		if _, ok := entry.Data[key]; !ok {
+			entry.Data[key] = value
+		}
+	}
+
+	return nil
+}
+
+// deliveryFields returns the fields describing a request to the target.
+func deliveryFields(target string, attempt int, start time.Time) logrus.Fields {
+	return logrus.Fields{
+		"target":  target,
+		"attempt": attempt,
+		"latency": time.Since(start).String(),
+	}
+}
+
+// urlHost returns the host of the url so the full
+// url, which may contain credentials, isn't logged.
+func urlHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	return u.Host
+}
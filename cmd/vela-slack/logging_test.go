@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_setLogFormat(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		format  string
+		want    logrus.Formatter
+		failure bool
+	}{
+		{format: "", want: new(logrus.TextFormatter)},
+		{format: logFormatText, want: new(logrus.TextFormatter)},
+		{format: logFormatJSON, want: new(logrus.JSONFormatter)},
+		{format: "xml", failure: true},
+	}
+
+	defer logrus.SetFormatter(new(logrus.TextFormatter))
+
+	// run tests
+	for _, test := range tests {
+		err := setLogFormat(test.format)
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("setLogFormat should have returned err for %s", test.format)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("setLogFormat returned err for %s: %v", test.format, err)
+		}
+
+		if fmt.Sprintf("%T", logrus.StandardLogger().Formatter) != fmt.Sprintf("%T", test.want) {
+			t.Errorf("setLogFormat formatter is %T, want %T", logrus.StandardLogger().Formatter, test.want)
+		}
+	}
+}
+
+func TestSlack_fieldsHook_Fire(t *testing.T) {
+	// setup types
+	h := &fieldsHook{fields: logrus.Fields{"build_repo": "octocat/hello-world", "build_number": 1}}
+
+	entry := &logrus.Entry{Data: logrus.Fields{"build_number": 2}}
+
+	err := h.Fire(entry)
+	if err != nil {
+		t.Errorf("Fire returned err: %v", err)
+	}
+
+	if entry.Data["build_repo"] != "octocat/hello-world" {
+		t.Errorf("Fire build_repo is %v", entry.Data["build_repo"])
+	}
+
+	if entry.Data["build_number"] != 2 {
+		t.Errorf("Fire build_number is %v, want 2", entry.Data["build_number"])
+	}
+}
+
+func TestSlack_Plugin_Exec_DeliveryFields(t *testing.T) {
+	// setup types
+	hook := test.NewLocal(logrus.StandardLogger())
+	defer logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, "ok")
+	}))
+	defer ts.Close()
+
+	p := &Plugin{
+		Webhook: ts.URL,
+		Env:     &Env{},
+		WebhookMsg: &slack.WebhookMessage{
+			Text: "hello",
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	var delivered *logrus.Entry
+
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "Delivered message" {
+			delivered = entry
+		}
+	}
+
+	if delivered == nil {
+		t.Fatal("Exec didn't log the delivered message")
+	}
+
+	if delivered.Data["target"] != urlHost(ts.URL) {
+		t.Errorf("delivered target is %v, want %s", delivered.Data["target"], urlHost(ts.URL))
+	}
+
+	if delivered.Data["attempt"] != 1 {
+		t.Errorf("delivered attempt is %v, want 1", delivered.Data["attempt"])
+	}
+
+	if _, ok := delivered.Data["latency"]; !ok {
+		t.Error("delivered entry is missing the latency")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/go-ldap/ldap/v3"
@@ -66,12 +67,42 @@ func main() {
 			Usage:    "set log level - options: (trace|debug|info|warn|error|fatal|panic)",
 			Value:    "info",
 		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_LOG_FORMAT", "SLACK_LOG_FORMAT"},
+			FilePath: "/vela/parameters/slack/log_format,/vela/secrets/slack/log_format",
+			Name:     "log.format",
+			Usage:    "set log format - options: (text|json)",
+			Value:    logFormatText,
+		},
 		&cli.StringFlag{
 			EnvVars:  []string{"PARAMETER_SSL_CERT_FILE", "SSL_CERT_FILE"},
 			FilePath: "/vela/parameters/sslcert/filepath,/vela/secrets/sslcert/filepath",
 			Name:     "sslcert.path",
 			Usage:    "path to ssl cert file",
 		},
+		&cli.StringSliceFlag{
+			FilePath: "/vela/secrets/slack/allowed_channels",
+			Name:     "allowed-channels",
+			Usage:    "channels messages are allowed to be sent to",
+		},
+		&cli.StringSliceFlag{
+			FilePath: "/vela/secrets/slack/allowed_hosts",
+			Name:     "allowed-hosts",
+			Usage:    "webhook and api hosts messages are allowed to be sent to",
+		},
+		&cli.DurationFlag{
+			EnvVars:  []string{"PARAMETER_TIMEOUT", "SLACK_TIMEOUT"},
+			FilePath: "/vela/parameters/slack/timeout,/vela/secrets/slack/timeout",
+			Name:     "timeout",
+			Usage:    "timeout for each outbound HTTP request",
+			Value:    30 * time.Second,
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_PROXY", "SLACK_PROXY"},
+			FilePath: "/vela/parameters/slack/proxy,/vela/secrets/slack/proxy",
+			Name:     "proxy",
+			Usage:    "proxy url for outbound HTTP requests",
+		},
 
 		// Config Flags
 
@@ -87,12 +118,275 @@ func main() {
 			Name:     "webhook",
 			Usage:    "slack webhook used to post log messages to channel",
 		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_PROVIDER", "SLACK_PROVIDER"},
+			FilePath: "/vela/parameters/slack/provider,/vela/secrets/slack/provider",
+			Name:     "provider",
+			Usage:    "backend to deliver the message with - options: (slack|slack-webhook|slack-api|mattermost|discord|googlechat|teams|webhook)",
+			Value:    providerSlack,
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_WEBHOOK_METHOD", "SLACK_WEBHOOK_METHOD"},
+			FilePath: "/vela/parameters/slack/webhook_method,/vela/secrets/slack/webhook_method",
+			Name:     "webhook-method",
+			Usage:    "http method for the webhook provider",
+			Value:    "POST",
+		},
+		&cli.StringSliceFlag{
+			EnvVars:  []string{"PARAMETER_WEBHOOK_HEADERS", "SLACK_WEBHOOK_HEADERS"},
+			FilePath: "/vela/parameters/slack/webhook_headers,/vela/secrets/slack/webhook_headers",
+			Name:     "webhook-headers",
+			Usage:    "headers in the Name: value format for the webhook provider",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_WEBHOOK_BODY", "SLACK_WEBHOOK_BODY"},
+			FilePath: "/vela/parameters/slack/webhook_body,/vela/secrets/slack/webhook_body",
+			Name:     "webhook-body",
+			Usage:    "body template for the webhook provider",
+		},
 		&cli.BoolFlag{
 			EnvVars:  []string{"PARAMETER_REMOTE", "SLACK_REMOTE"},
 			FilePath: "/vela/parameters/slack/remote,/vela/secrets/slack/remote",
 			Name:     "remote",
 			Usage:    "if filepath is remote or not",
 		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_CONFIG", "SLACK_CONFIG"},
+			FilePath: "/vela/parameters/slack/config,/vela/secrets/slack/config",
+			Name:     "config",
+			Usage:    "YAML file with the parameters of the plugin",
+		},
+		&cli.BoolFlag{
+			EnvVars:  []string{"PARAMETER_CONFIG_REMOTE", "SLACK_CONFIG_REMOTE"},
+			FilePath: "/vela/parameters/slack/config_remote,/vela/secrets/slack/config_remote",
+			Name:     "config-remote",
+			Usage:    "if the config file is remote or not",
+		},
+		&cli.BoolFlag{
+			EnvVars:  []string{"PARAMETER_FALLBACK_ON_ERROR", "SLACK_FALLBACK_ON_ERROR"},
+			FilePath: "/vela/parameters/slack/fallback_on_error,/vela/secrets/slack/fallback_on_error",
+			Name:     "fallback-on-error",
+			Usage:    "post a plain-text message when the message template fails to render",
+		},
+		&cli.BoolFlag{
+			EnvVars:  []string{"PARAMETER_CONTINUE_ON_ERROR", "SLACK_CONTINUE_ON_ERROR"},
+			FilePath: "/vela/parameters/slack/continue_on_error,/vela/secrets/slack/continue_on_error",
+			Name:     "continue-on-error",
+			Usage:    "succeed when the notification can't be delivered",
+		},
+		&cli.BoolFlag{
+			EnvVars:  []string{"PARAMETER_FAIL_ON_TEMPLATE_ERROR", "SLACK_FAIL_ON_TEMPLATE_ERROR"},
+			FilePath: "/vela/parameters/slack/fail_on_template_error,/vela/secrets/slack/fail_on_template_error",
+			Name:     "fail-on-template-error",
+			Usage:    "fail on template errors when continuing on errors",
+			Value:    true,
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_BOT_TOKEN", "SLACK_BOT_TOKEN"},
+			FilePath: "/vela/parameters/slack/bot_token,/vela/secrets/slack/bot_token",
+			Name:     "bot-token",
+			Usage:    "slack bot token used to post messages with the Slack API",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_API_URL", "SLACK_API_URL"},
+			FilePath: "/vela/parameters/slack/api_url,/vela/secrets/slack/api_url",
+			Name:     "api-url",
+			Usage:    "slack api url used with the bot token",
+			Value:    slack.APIURL,
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_OVERFLOW", "SLACK_OVERFLOW"},
+			FilePath: "/vela/parameters/slack/overflow,/vela/secrets/slack/overflow",
+			Name:     "overflow",
+			Usage:    "handling for messages exceeding slack limits - options: (truncate|split)",
+			Value:    overflowTruncate,
+		},
+		&cli.StringSliceFlag{
+			EnvVars:  []string{"PARAMETER_FILES", "SLACK_FILES"},
+			FilePath: "/vela/parameters/slack/files,/vela/secrets/slack/files",
+			Name:     "files",
+			Usage:    "workspace file patterns to upload to the channel with the bot token",
+		},
+		&cli.BoolFlag{
+			EnvVars:  []string{"PARAMETER_FILES_THREAD", "SLACK_FILES_THREAD"},
+			FilePath: "/vela/parameters/slack/files_thread,/vela/secrets/slack/files_thread",
+			Name:     "files-thread",
+			Usage:    "share the uploaded files into the thread of the message",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_LOG_FILE", "SLACK_LOG_FILE"},
+			FilePath: "/vela/parameters/slack/log_file,/vela/secrets/slack/log_file",
+			Name:     "log-file",
+			Usage:    "workspace log file to include the last lines of in the template",
+		},
+		&cli.IntFlag{
+			EnvVars:  []string{"PARAMETER_LOG_LINES", "SLACK_LOG_LINES"},
+			FilePath: "/vela/parameters/slack/log_lines,/vela/secrets/slack/log_lines",
+			Name:     "log-lines",
+			Usage:    "number of lines to include from the log file",
+			Value:    20,
+		},
+		&cli.StringSliceFlag{
+			EnvVars:  []string{"PARAMETER_JUNIT", "SLACK_JUNIT"},
+			FilePath: "/vela/parameters/slack/junit,/vela/secrets/slack/junit",
+			Name:     "junit",
+			Usage:    "workspace junit xml report patterns to summarize in the template",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_MODE", "SLACK_MODE"},
+			FilePath: "/vela/parameters/slack/mode,/vela/secrets/slack/mode",
+			Name:     "mode",
+			Usage:    "how to deliver the notification - options: (post|reaction|cancel)",
+			Value:    modePost,
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_MESSAGE_TS", "SLACK_MESSAGE_TS"},
+			FilePath: "/vela/parameters/slack/message_ts,/vela/secrets/slack/message_ts",
+			Name:     "message-ts",
+			Usage:    "timestamp of the message to add or remove reactions on",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_TS_FILE", "SLACK_TS_FILE"},
+			FilePath: "/vela/parameters/slack/ts_file,/vela/secrets/slack/ts_file",
+			Name:     "ts-file",
+			Usage:    "file to store the posted message in or read the message to react to from",
+		},
+		&cli.BoolFlag{
+			EnvVars:  []string{"PARAMETER_CHANGELOG", "SLACK_CHANGELOG"},
+			FilePath: "/vela/parameters/slack/changelog,/vela/secrets/slack/changelog",
+			Name:     "changelog",
+			Usage:    "add the commits since the previous successful build to templates",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_CHANGELOG_BASE", "SLACK_CHANGELOG_BASE"},
+			FilePath: "/vela/parameters/slack/changelog_base,/vela/secrets/slack/changelog_base",
+			Name:     "changelog-base",
+			Usage:    "ref to create the changelog from instead of the previous successful build",
+		},
+		&cli.IntFlag{
+			EnvVars:  []string{"PARAMETER_CHANGELOG_LIMIT", "SLACK_CHANGELOG_LIMIT"},
+			FilePath: "/vela/parameters/slack/changelog_limit,/vela/secrets/slack/changelog_limit",
+			Name:     "changelog-limit",
+			Usage:    "maximum number of commits in the changelog",
+			Value:    50,
+		},
+		&cli.BoolFlag{
+			EnvVars:  []string{"PARAMETER_VELA_API", "SLACK_VELA_API"},
+			FilePath: "/vela/parameters/slack/vela_api,/vela/secrets/slack/vela_api",
+			Name:     "vela-api",
+			Usage:    "add the steps of the build from the Vela API to templates",
+		},
+		&cli.StringFlag{
+			// the address is only read from Vela so the token can't be sent elsewhere
+			EnvVars: []string{"VELA_SERVER_ADDR"},
+			Name:    "vela-addr",
+			Usage:   "environment variable reference for reading in Vela server address",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_VELA_TOKEN", "VELA_TOKEN", "VELA_NETRC_PASSWORD"},
+			FilePath: "/vela/parameters/slack/vela_token,/vela/secrets/slack/vela_token",
+			Name:     "vela-token",
+			Usage:    "token for the Vela API",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_NOTIFY_ON", "SLACK_NOTIFY_ON"},
+			FilePath: "/vela/parameters/slack/notify_on,/vela/secrets/slack/notify_on",
+			Name:     "notify-on",
+			Usage:    "when to send the message (always or change)",
+			Value:    notifyAlways,
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_STATE_FILE", "SLACK_STATE_FILE"},
+			FilePath: "/vela/parameters/slack/state_file,/vela/secrets/slack/state_file",
+			Name:     "state-file",
+			Usage:    "file storing the status of previous builds for notify_on change",
+		},
+		&cli.StringSliceFlag{
+			EnvVars:  []string{"PARAMETER_QUIET_HOURS", "SLACK_QUIET_HOURS"},
+			FilePath: "/vela/parameters/slack/quiet_hours,/vela/secrets/slack/quiet_hours",
+			Name:     "quiet-hours",
+			Usage:    "ranges of time non-failure messages aren't sent during, e.g. Mon-Fri 18:00-08:00",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_QUIET_TIMEZONE", "SLACK_QUIET_TIMEZONE"},
+			FilePath: "/vela/parameters/slack/quiet_timezone,/vela/secrets/slack/quiet_timezone",
+			Name:     "quiet-timezone",
+			Usage:    "timezone of the quiet hours",
+			Value:    "UTC",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_QUIET_CHANNEL", "SLACK_QUIET_CHANNEL"},
+			FilePath: "/vela/parameters/slack/quiet_channel,/vela/secrets/slack/quiet_channel",
+			Name:     "quiet-channel",
+			Usage:    "channel messages are rerouted to during the quiet hours",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_QUIET_WEBHOOK", "SLACK_QUIET_WEBHOOK"},
+			FilePath: "/vela/parameters/slack/quiet_webhook,/vela/secrets/slack/quiet_webhook",
+			Name:     "quiet-webhook",
+			Usage:    "webhook messages are rerouted to during the quiet hours",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_ROUTES", "SLACK_ROUTES"},
+			FilePath: "/vela/parameters/slack/routes,/vela/secrets/slack/routes",
+			Name:     "routes",
+			Usage:    "list of rules sending messages for matching builds to channels",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_ROUTE_MATCH", "SLACK_ROUTE_MATCH"},
+			FilePath: "/vela/parameters/slack/route_match,/vela/secrets/slack/route_match",
+			Name:     "route-match",
+			Usage:    "whether the first or all matching routes are used (first or all)",
+			Value:    routeMatchFirst,
+		},
+		&cli.StringSliceFlag{
+			EnvVars:  []string{"PARAMETER_VARS", "SLACK_VARS"},
+			FilePath: "/vela/parameters/slack/vars,/vela/secrets/slack/vars",
+			Name:     "vars",
+			Usage:    "names or patterns of environment variables to expose to templates",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_RESULT_FILE", "SLACK_RESULT_FILE"},
+			FilePath: "/vela/parameters/slack/result_file,/vela/secrets/slack/result_file",
+			Name:     "result-file",
+			Usage:    "file to write the outcome of the plugin to as JSON",
+		},
+		&cli.StringSliceFlag{
+			EnvVars:  []string{"PARAMETER_REACTIONS_ADD", "SLACK_REACTIONS_ADD"},
+			FilePath: "/vela/parameters/slack/reactions_add,/vela/secrets/slack/reactions_add",
+			Name:     "reactions-add",
+			Usage:    "emoji reactions to add to the message",
+		},
+		&cli.StringSliceFlag{
+			EnvVars:  []string{"PARAMETER_REACTIONS_REMOVE", "SLACK_REACTIONS_REMOVE"},
+			FilePath: "/vela/parameters/slack/reactions_remove,/vela/secrets/slack/reactions_remove",
+			Name:     "reactions-remove",
+			Usage:    "emoji reactions to remove from the message",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_POST_AT", "SLACK_POST_AT"},
+			FilePath: "/vela/parameters/slack/post_at,/vela/secrets/slack/post_at",
+			Name:     "post-at",
+			Usage:    "time or duration from now to schedule the message for",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_SCHEDULED_MESSAGE_ID", "SLACK_SCHEDULED_MESSAGE_ID"},
+			FilePath: "/vela/parameters/slack/scheduled_message_id,/vela/secrets/slack/scheduled_message_id",
+			Name:     "scheduled-message-id",
+			Usage:    "id of the scheduled message to cancel",
+		},
+		&cli.BoolFlag{
+			EnvVars:  []string{"PARAMETER_DM_AUTHOR", "SLACK_DM_AUTHOR"},
+			FilePath: "/vela/parameters/slack/dm_author,/vela/secrets/slack/dm_author",
+			Name:     "dm-author",
+			Usage:    "send the message as a direct message to the build author",
+		},
+		&cli.StringFlag{
+			EnvVars:  []string{"PARAMETER_EPHEMERAL_USER", "SLACK_EPHEMERAL_USER"},
+			FilePath: "/vela/parameters/slack/ephemeral_user,/vela/secrets/slack/ephemeral_user",
+			Name:     "ephemeral-user",
+			Usage:    "slack user id or email to send the message to as an ephemeral message",
+		},
 
 		// Webhook Flags
 
@@ -211,6 +505,11 @@ func main() {
 			Name:    "build-parent",
 			Usage:   "environment variable reference for reading in build parent",
 		},
+		&cli.IntFlag{
+			EnvVars: []string{"VELA_PULL_REQUEST", "VELA_BUILD_PULL_REQUEST", "BUILD_PULL_REQUEST_NUMBER"},
+			Name:    "build-pull-request",
+			Usage:   "environment variable reference for reading in build pull request number",
+		},
 		&cli.StringFlag{
 			EnvVars: []string{"VELA_BUILD_REF", "BUILD_REF"},
 			Name:    "build-ref",
@@ -231,6 +530,11 @@ func main() {
 			Name:    "build-source",
 			Usage:   "environment variable reference for reading in build source",
 		},
+		&cli.StringFlag{
+			EnvVars: []string{"VELA_BUILD_STATUS", "BUILD_STATUS"},
+			Name:    "build-status",
+			Usage:   "environment variable reference for reading in build status",
+		},
 		&cli.StringFlag{
 			EnvVars: []string{"VELA_BUILD_TAG", "BUILD_TAG"},
 			Name:    "build-tag",
@@ -247,6 +551,37 @@ func main() {
 			Usage:   "environment variable reference for reading in build workspace",
 		},
 
+		// Pull Request Environment Variable Flags
+
+		&cli.StringFlag{
+			EnvVars: []string{"VELA_PULL_REQUEST_SOURCE"},
+			Name:    "pull-request-source",
+			Usage:   "environment variable reference for reading in pull request source branch",
+		},
+		&cli.StringFlag{
+			EnvVars: []string{"VELA_PULL_REQUEST_TARGET"},
+			Name:    "pull-request-target",
+			Usage:   "environment variable reference for reading in pull request target branch",
+		},
+
+		// Deployment Environment Variable Flags
+
+		&cli.IntFlag{
+			EnvVars: []string{"VELA_DEPLOYMENT_NUMBER"},
+			Name:    "deployment-number",
+			Usage:   "environment variable reference for reading in deployment number",
+		},
+		&cli.StringFlag{
+			EnvVars: []string{"VELA_DEPLOYMENT", "VELA_BUILD_TARGET", "BUILD_TARGET"},
+			Name:    "deployment-target",
+			Usage:   "environment variable reference for reading in deployment target",
+		},
+		&cli.StringFlag{
+			EnvVars: []string{"VELA_DEPLOYMENT_TASK"},
+			Name:    "deployment-task",
+			Usage:   "environment variable reference for reading in deployment task",
+		},
+
 		// Repository Environment Variable Flags
 
 		&cli.StringFlag{
@@ -345,12 +680,22 @@ func main() {
 
 	err = app.Run(os.Args)
 	if err != nil {
-		logrus.Fatal(err)
+		logrus.Error(err)
+
+		os.Exit(exitCode(err))
 	}
 }
 
 // run executes the plugin based off the configuration provided.
+//
+//nolint:funlen // ignore length for run
 func run(c *cli.Context) error {
+	// apply the parameters from the config file
+	err := loadConfig(c)
+	if err != nil {
+		return classify(classConfig, err)
+	}
+
 	// set the log level for the plugin
 	switch c.String("log.level") {
 	case "t", "trace", "Trace", "TRACE":
@@ -371,12 +716,44 @@ func run(c *cli.Context) error {
 		logrus.SetLevel(logrus.InfoLevel)
 	}
 
+	// set the log format for the plugin
+	err = setLogFormat(c.String("log.format"))
+	if err != nil {
+		return classify(classConfig, err)
+	}
+
+	// add the build to every log entry
+	logrus.AddHook(&fieldsHook{fields: logrus.Fields{
+		"build_repo":   c.String("repo-full-name"),
+		"build_number": c.Int("build-number"),
+	}})
+
 	logrus.WithFields(logrus.Fields{
 		"code":     "https://github.com/go-vela/vela-slack",
 		"docs":     "https://go-vela.github.io/docs/plugins/registry/pipeline/slack",
 		"registry": "https://hub.docker.com/r/target/vela-slack",
 	}).Info("Vela Slack Plugin")
 
+	// remove secret values from the logs and messages
+	redactor := newRedactor(secretValues(c)...)
+
+	logrus.AddHook(&redactHook{replacer: redactor})
+
+	// create the client for outbound HTTP requests
+	client, err := newHTTPClient(c.Duration("timeout"), c.String("proxy"), c.String("sslcert.path"))
+	if err != nil {
+		return classify(classConfig, err)
+	}
+
+	// parse the rules for routing messages to channels
+	routes, err := parseRoutes(c.String("routes"))
+	if err != nil {
+		return classify(classConfig, err)
+	}
+
+	// capture the pull request number from the environment or build ref
+	pullRequest := pullRequestNumber(c.Int("build-pull-request"), c.String("build-ref"))
+
 	// create the plugin
 	p := &Plugin{
 		Webhook: c.String("webhook"),
@@ -390,7 +767,51 @@ func run(c *cli.Context) error {
 			Text:            c.String("text"),
 			Parse:           c.String("parse"),
 		},
-		Remote: c.Bool("remote"),
+		Remote:              c.Bool("remote"),
+		FallbackOnError:     c.Bool("fallback-on-error"),
+		ContinueOnError:     c.Bool("continue-on-error"),
+		FailOnTemplateError: c.Bool("fail-on-template-error"),
+		BotToken:            c.String("bot-token"),
+		APIURL:              c.String("api-url"),
+		Overflow:            c.String("overflow"),
+		Files:               c.StringSlice("files"),
+		FilesThread:         c.Bool("files-thread"),
+		LogFile:             c.String("log-file"),
+		LogLines:            c.Int("log-lines"),
+		JUnit:               c.StringSlice("junit"),
+		Mode:                c.String("mode"),
+		MessageTS:           c.String("message-ts"),
+		TSFile:              c.String("ts-file"),
+		ResultFile:          c.String("result-file"),
+		ReactionsAdd:        c.StringSlice("reactions-add"),
+		ReactionsRemove:     c.StringSlice("reactions-remove"),
+		PostAt:              c.String("post-at"),
+		ScheduledMessageID:  c.String("scheduled-message-id"),
+		DMAuthor:            c.Bool("dm-author"),
+		EphemeralUser:       c.String("ephemeral-user"),
+		Provider:            c.String("provider"),
+		WebhookMethod:       c.String("webhook-method"),
+		WebhookHeaders:      c.StringSlice("webhook-headers"),
+		WebhookBody:         c.String("webhook-body"),
+		HTTPClient:          client,
+		Token:               c.String("token"),
+		Redactor:            redactor,
+		Changelog:           c.Bool("changelog"),
+		ChangelogBase:       c.String("changelog-base"),
+		ChangelogLimit:      c.Int("changelog-limit"),
+		VelaAPI:             c.Bool("vela-api"),
+		VelaAddr:            c.String("vela-addr"),
+		VelaToken:           c.String("vela-token"),
+		NotifyOn:            c.String("notify-on"),
+		StateFile:           c.String("state-file"),
+		QuietHours:          c.StringSlice("quiet-hours"),
+		QuietTimezone:       c.String("quiet-timezone"),
+		QuietChannel:        c.String("quiet-channel"),
+		QuietWebhook:        c.String("quiet-webhook"),
+		Routes:              routes,
+		RouteMatch:          c.String("route-match"),
+		AllowedChannels:     c.StringSlice("allowed-channels"),
+		AllowedHosts:        c.StringSlice("allowed-hosts"),
 		Env: &Env{
 			BuildAuthor:               c.String("build-author"),
 			BuildAuthorEmail:          c.String("build-author-email"),
@@ -407,10 +828,12 @@ func run(c *cli.Context) error {
 			BuildMessage:              c.String("build-message"),
 			BuildNumber:               c.Int("build-number"),
 			BuildParent:               c.Int("build-parent"),
+			BuildPullRequest:          pullRequest,
 			BuildRef:                  c.String("build-ref"),
 			BuildSender:               c.String("build-sender"),
 			BuildStarted:              c.Int("build-started"),
 			BuildSource:               c.String("build-source"),
+			BuildStatus:               c.String("build-status"),
 			BuildTag:                  c.String("build-tag"),
 			BuildTitle:                c.String("build-title"),
 			BuildWorkspace:            c.String("build-workspace"),
@@ -433,13 +856,23 @@ func run(c *cli.Context) error {
 			RepoTimeout:               c.Int("repo-timeout"),
 			RepositoryTrusted:         c.String("repo-trusted"),
 			RepoTrusted:               c.String("repo-trusted"),
-			Token:                     c.String("token"),
+			PullRequestSource:         c.String("pull-request-source"),
+			PullRequestTarget:         c.String("pull-request-target"),
+			PullRequestTitle:          pullRequestTitle(c.String("build-event"), c.String("build-message")),
+			PullRequestLink:           pullRequestLink(c.String("repo-link"), pullRequest),
+			Vars:                      loadVars(os.Environ(), c.StringSlice("vars")),
+			Deployment:                getDeployment(c, os.Environ()),
 		},
 	}
 
 	// validate the plugin
-	err := p.Validate()
+	err = p.Validate()
 	if err != nil {
+		err = classify(classConfig, err)
+
+		// record the outcome for wrapper pipelines
+		p.writeResult(nil, err, false)
+
 		return err
 	}
 
@@ -462,12 +895,14 @@ func getSAMAccountName(c *cli.Context) string {
 		return ""
 	}
 
+	logger := logrus.WithField("target", ldapServer)
+
 	// create LDAP client
 	roots := x509.NewCertPool()
 
 	caCerts, err := os.ReadFile(c.String("sslcert.path"))
 	if err != nil {
-		logrus.Errorf("%s", err)
+		logger.Errorf("%s", err)
 		return ""
 	}
 
@@ -483,16 +918,18 @@ func getSAMAccountName(c *cli.Context) string {
 	// TODO: allow to define scheme as a plugin option
 	serverFQDN := fmt.Sprintf("ldaps://%s:%s", ldapServer, ldapPort)
 
+	start := time.Now()
+
 	l, err := ldap.DialURL(serverFQDN, ldap.DialWithTLSConfig(configTLS))
 	if err != nil {
-		logrus.Errorf("%s", err)
+		logger.Errorf("%s", err)
 		return ""
 	}
 	defer l.Close()
 
 	err = l.Bind(username, password)
 	if err != nil {
-		logrus.Errorf("%s", err)
+		logger.Errorf("%s", err)
 		return ""
 	}
 
@@ -508,17 +945,57 @@ func getSAMAccountName(c *cli.Context) string {
 	// search for records
 	sr, err := l.Search(req)
 	if err != nil {
-		logrus.Errorf("%s", err)
+		logger.Errorf("%s", err)
 		return ""
 	}
 
 	if len(sr.Entries) != 1 {
-		logrus.Errorf("user does not exist or too many entries returned: %d", len(sr.Entries))
+		logger.Errorf("user does not exist or too many entries returned: %d", len(sr.Entries))
 		return ""
 	}
 
 	// return sAMAccountName
 	sAMAccountName := sr.Entries[0].GetAttributeValue("sAMAccountName")
 
+	logrus.WithFields(deliveryFields(ldapServer, 1, start)).Debug("Found LDAP sAMAccountName")
+
 	return sAMAccountName
 }
+
+// secretValues returns the values of the flags containing secrets.
+func secretValues(c *cli.Context) []string {
+	secrets := []string{
+		c.String("token"),
+		c.String("ldap-password"),
+		c.String("webhook"),
+		c.String("quiet-webhook"),
+		c.String("bot-token"),
+		c.String("vela-token"),
+	}
+
+	// include the values of headers used for credentials
+	for _, header := range c.StringSlice("webhook-headers") {
+		name, value, ok := strings.Cut(header, ":")
+		if ok && isSecretName(name) {
+			secrets = append(secrets, strings.TrimSpace(value))
+		}
+	}
+
+	return secrets
+}
+
+// getDeployment returns the deployment for deployment events. The
+// description of the deployment is used as the build message by Vela.
+func getDeployment(c *cli.Context, environ []string) Deployment {
+	if c.String("build-event") != eventDeployment {
+		return Deployment{}
+	}
+
+	return Deployment{
+		Number:      c.Int("deployment-number"),
+		Target:      escapeJSON(c.String("deployment-target")),
+		Task:        escapeJSON(c.String("deployment-task")),
+		Description: escapeJSON(c.String("build-message")),
+		Parameters:  deploymentParameters(environ),
+	}
+}
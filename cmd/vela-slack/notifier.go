@@ -0,0 +1,329 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// providers for delivering the message.
+const (
+	providerSlack        = "slack"
+	providerSlackWebhook = "slack-webhook"
+	providerSlackAPI     = "slack-api"
+	providerMattermost   = "mattermost"
+	providerDiscord      = "discord"
+	providerGoogleChat   = "googlechat"
+	providerTeams        = "teams"
+	providerWebhook      = "webhook"
+)
+
+// slackLinkRegex matches Slack formatted links like <url|text> and <url>.
+var slackLinkRegex = regexp.MustCompile(`<((?:https?|mailto):[^|>]+)(?:\|([^>]+))?>`)
+
+type (
+	// Notifier represents a backend for delivering the message.
+	Notifier interface {
+		// Notify delivers the message and returns the channel id
+		// and timestamp of the posted message when they are known.
+		Notify(ctx context.Context, msg *slack.WebhookMessage) (string, string, error)
+	}
+
+	// slackWebhook delivers messages with a Slack incoming webhook.
+	slackWebhook struct {
+		url    string
+		client *http.Client
+	}
+
+	// slackAPI delivers messages with the Slack Web API.
+	slackAPI struct {
+		client        *slack.Client
+		ephemeralUser string
+	}
+
+	// mattermost delivers messages with a Mattermost incoming webhook.
+	mattermost struct {
+		url    string
+		client *http.Client
+	}
+)
+
+// notifier creates the notifier for the configured provider.
+//
+// The slack provider uses the Web API when a bot token is provided
+// since incoming webhooks don't return the posted message.
+func (p *Plugin) notifier() (Notifier, error) {
+	switch p.Provider {
+	case "", providerSlack:
+		if len(p.BotToken) > 0 {
+			return &slackAPI{client: p.client(), ephemeralUser: p.EphemeralUser}, nil
+		}
+
+		return &slackWebhook{url: p.Webhook, client: p.httpClient()}, nil
+	case providerSlackWebhook:
+		return &slackWebhook{url: p.Webhook, client: p.httpClient()}, nil
+	case providerSlackAPI:
+		return &slackAPI{client: p.client(), ephemeralUser: p.EphemeralUser}, nil
+	case providerMattermost:
+		return &mattermost{url: p.Webhook, client: p.httpClient()}, nil
+	case providerDiscord:
+		return &discord{url: p.Webhook, client: p.httpClient()}, nil
+	case providerGoogleChat:
+		return &googleChat{url: p.Webhook, client: p.httpClient()}, nil
+	case providerTeams:
+		return &teams{url: p.Webhook, client: p.httpClient()}, nil
+	default:
+		return nil, fmt.Errorf("invalid provider provided: %s", p.Provider)
+	}
+}
+
+// usesAPI checks if the provider delivers messages with the Slack Web API.
+func (p *Plugin) usesAPI() bool {
+	switch p.Provider {
+	case "", providerSlack:
+		return len(p.BotToken) > 0
+	case providerSlackAPI:
+		return true
+	default:
+		return false
+	}
+}
+
+// post sends the message with the notifier for the provider and returns
+// the channel id and timestamp of the posted message when they are known.
+func (p *Plugin) post(ctx context.Context, msg *slack.WebhookMessage) (string, string, error) {
+	n, err := p.notifier()
+	if err != nil {
+		return "", "", err
+	}
+
+	start := time.Now()
+
+	channel, ts, err := n.Notify(ctx, msg)
+
+	fields := deliveryFields(p.target(msg), 1, start)
+	if err != nil {
+		logrus.WithFields(fields).Warn("Unable to deliver message")
+
+		return "", "", err
+	}
+
+	logrus.WithFields(fields).Info("Delivered message")
+
+	return channel, ts, nil
+}
+
+// target returns the channel or webhook host the message is delivered to.
+func (p *Plugin) target(msg *slack.WebhookMessage) string {
+	if p.usesAPI() || len(msg.Channel) > 0 {
+		return msg.Channel
+	}
+
+	return urlHost(p.Webhook)
+}
+
+// Notify posts the message to the Slack incoming webhook.
+func (n *slackWebhook) Notify(ctx context.Context, msg *slack.WebhookMessage) (string, string, error) {
+	logrus.Info("Posting webhook message...")
+
+	err := slack.PostWebhookCustomHTTPContext(ctx, n.url, n.client, msg)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to post webhook message: %w", err)
+	}
+
+	return "", "", nil
+}
+
+// Notify posts the message with the Slack Web API.
+func (n *slackAPI) Notify(ctx context.Context, msg *slack.WebhookMessage) (string, string, error) {
+	if len(n.ephemeralUser) > 0 {
+		logrus.Info("Posting ephemeral message with Slack API...")
+
+		_, err := n.client.PostEphemeralContext(ctx, msg.Channel, n.ephemeralUser, msgOptions(msg)...)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to post ephemeral message: %w", err)
+		}
+
+		// ephemeral messages can't be referenced by later messages
+		return msg.Channel, "", nil
+	}
+
+	logrus.Info("Posting message with Slack API...")
+
+	channel, ts, err := n.client.PostMessageContext(ctx, msg.Channel, msgOptions(msg)...)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to post message: %w", err)
+	}
+
+	logrus.Debugf("posted message %s to channel %s", ts, channel)
+
+	return channel, ts, nil
+}
+
+// Notify posts the message to the Mattermost incoming webhook.
+//
+// Mattermost accepts the Slack payload except for blocks
+// and uses markdown instead of Slack formatted links.
+//
+// https://developers.mattermost.com/integrate/webhooks/incoming/
+func (n *mattermost) Notify(ctx context.Context, msg *slack.WebhookMessage) (string, string, error) {
+	logrus.Info("Posting Mattermost webhook message...")
+
+	m := *msg
+	m.Blocks = nil
+	m.Text = markdownLinks(m.Text)
+	m.Attachments = make([]slack.Attachment, len(msg.Attachments))
+
+	for i, a := range msg.Attachments {
+		a.Pretext = markdownLinks(a.Pretext)
+		a.Text = markdownLinks(a.Text)
+
+		fields := make([]slack.AttachmentField, len(a.Fields))
+
+		for j, f := range a.Fields {
+			f.Value = markdownLinks(f.Value)
+			fields[j] = f
+		}
+
+		a.Fields = fields
+		m.Attachments[i] = a
+	}
+
+	err := postJSON(ctx, n.client, n.url, &m)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to post Mattermost webhook message: %w", err)
+	}
+
+	return "", "", nil
+}
+
+// postJSON sends the payload as JSON to the url.
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("unable to marshal payload: %w", err)
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+
+	return sendRequest(ctx, client, http.MethodPost, url, header, body)
+}
+
+// sendRequest sends the body to the url with the method and headers
+// and returns an error when a successful status isn't received.
+func sendRequest(ctx context.Context, client *http.Client, method, url string, header http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header = header
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send request: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return fmt.Errorf("received status %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	return nil
+}
+
+// markdownLinks converts Slack formatted links into markdown links.
+func markdownLinks(s string) string {
+	return slackLinkRegex.ReplaceAllStringFunc(s, func(m string) string {
+		parts := slackLinkRegex.FindStringSubmatch(m)
+		if len(parts[2]) == 0 {
+			return parts[1]
+		}
+
+		return fmt.Sprintf("[%s](%s)", parts[2], parts[1])
+	})
+}
+
+// hexColor converts the attachment color into a hex color,
+// supporting the good, warning and danger Slack colors.
+func hexColor(color string) string {
+	switch color {
+	case "good":
+		return colorGood
+	case "warning":
+		return colorWarning
+	case "danger":
+		return colorDanger
+	case "":
+		return ""
+	}
+
+	if !strings.HasPrefix(color, "#") {
+		return "#" + color
+	}
+
+	return color
+}
+
+// intColor converts the attachment color into an integer color.
+func intColor(color string) int {
+	c, err := strconv.ParseInt(strings.TrimPrefix(hexColor(color), "#"), 16, 32)
+	if err != nil {
+		return 0
+	}
+
+	return int(c)
+}
+
+// joinLines joins the non-empty strings with newlines.
+func joinLines(lines ...string) string {
+	var parts []string
+
+	for _, line := range lines {
+		if len(line) > 0 {
+			parts = append(parts, line)
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// validateProvider validates the provider has the configuration it requires.
+func (p *Plugin) validateProvider() error {
+	switch p.Provider {
+	case "", providerSlack:
+		return nil
+	case providerSlackAPI:
+		// validate that a bot token was supplied
+		if len(p.BotToken) == 0 {
+			return fmt.Errorf("no bot token provided for %s provider", p.Provider)
+		}
+
+		return nil
+	case providerSlackWebhook, providerMattermost, providerDiscord, providerGoogleChat, providerTeams, providerWebhook:
+		// validate that a webhook was supplied
+		if len(p.Webhook) == 0 {
+			return fmt.Errorf("no webhook provided for %s provider", p.Provider)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("invalid provider provided: %s", p.Provider)
+	}
+}
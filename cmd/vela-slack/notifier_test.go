@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_Plugin_notifier(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		provider string
+		token    string
+		want     Notifier
+	}{
+		{provider: "", want: new(slackWebhook)},
+		{provider: providerSlack, token: "xoxb-token", want: new(slackAPI)},
+		{provider: providerSlackWebhook, token: "xoxb-token", want: new(slackWebhook)},
+		{provider: providerSlackAPI, token: "xoxb-token", want: new(slackAPI)},
+		{provider: providerMattermost, want: new(mattermost)},
+		{provider: providerDiscord, want: new(discord)},
+		{provider: providerGoogleChat, want: new(googleChat)},
+		{provider: providerTeams, want: new(teams)},
+	}
+
+	// run tests
+	for _, test := range tests {
+		p := &Plugin{Webhook: "webhook_url", BotToken: test.token, Provider: test.provider}
+
+		got, err := p.notifier()
+		if err != nil {
+			t.Errorf("notifier returned err: %v", err)
+		}
+
+		if reflect.TypeOf(got) != reflect.TypeOf(test.want) {
+			t.Errorf("notifier for %q is %T, want %T", test.provider, got, test.want)
+		}
+	}
+
+	p := &Plugin{Webhook: "webhook_url", Provider: "pager"}
+
+	_, err := p.notifier()
+	if err == nil {
+		t.Error("notifier should return err due to invalid provider")
+	}
+}
+
+func TestSlack_Plugin_Validate_Provider(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		p    *Plugin
+	}{
+		{
+			name: "invalid provider",
+			p:    &Plugin{Webhook: "webhook_url", Provider: "pager"},
+		},
+		{
+			name: "missing bot token for slack-api",
+			p:    &Plugin{Webhook: "webhook_url", Provider: providerSlackAPI},
+		},
+		{
+			name: "missing webhook for teams",
+			p:    &Plugin{BotToken: "xoxb-token", Provider: providerTeams},
+		},
+		{
+			name: "files with discord",
+			p:    &Plugin{Webhook: "webhook_url", BotToken: "xoxb-token", Provider: providerDiscord, Files: []string{"*.xml"}},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		test.p.Env = &Env{}
+		test.p.WebhookMsg = &slack.WebhookMessage{Channel: "#builds", Text: "hello"}
+
+		err := test.p.Validate()
+		if err == nil {
+			t.Errorf("Validate should return err due to %s", test.name)
+		}
+	}
+}
+
+func TestSlack_markdownLinks(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "no links", want: "no links"},
+		{input: "build <https://vela.example.com/1|#1> failed", want: "build [#1](https://vela.example.com/1) failed"},
+		{input: "see <https://vela.example.com>", want: "see https://vela.example.com"},
+		{input: "hi <@U024BE7LH> <!here>", want: "hi <@U024BE7LH> <!here>"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := markdownLinks(test.input)
+
+		if got != test.want {
+			t.Errorf("markdownLinks is %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestSlack_hexColor_intColor(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		color string
+		hex   string
+		int   int
+	}{
+		{color: "", hex: "", int: 0},
+		{color: "good", hex: colorGood, int: 0x2eb886},
+		{color: "danger", hex: colorDanger, int: 0xa30200},
+		{color: "#36a64f", hex: "#36a64f", int: 0x36a64f},
+		{color: "36a64f", hex: "#36a64f", int: 0x36a64f},
+	}
+
+	// run tests
+	for _, test := range tests {
+		if got := hexColor(test.color); got != test.hex {
+			t.Errorf("hexColor for %s is %s, want %s", test.color, got, test.hex)
+		}
+
+		if got := intColor(test.color); got != test.int {
+			t.Errorf("intColor for %s is %d, want %d", test.color, got, test.int)
+		}
+	}
+}
+
+func TestSlack_Plugin_Exec_Mattermost(t *testing.T) {
+	// setup types
+	var posted map[string]interface{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&posted)
+		if err != nil {
+			t.Errorf("Decode error: %v", err)
+		}
+
+		fmt.Fprintln(w, "ok")
+	}))
+	defer ts.Close()
+
+	p := &Plugin{
+		Webhook:  ts.URL,
+		Env:      &Env{BuildLink: "https://vela.example.com/1"},
+		Path:     "testdata/slack_attachment.json",
+		Provider: providerMattermost,
+		WebhookMsg: &slack.WebhookMessage{
+			Channel: "town-square",
+			Text:    "{{ slackLink .BuildLink \"Build\" }} finished",
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	if posted["text"] != "[Build](https://vela.example.com/1) finished" {
+		t.Errorf("Exec posted text %v", posted["text"])
+	}
+
+	if posted["channel"] != "town-square" {
+		t.Errorf("Exec posted channel %v", posted["channel"])
+	}
+
+	if attachments, ok := posted["attachments"].([]interface{}); !ok || len(attachments) != 1 {
+		t.Errorf("Exec posted attachments %v", posted["attachments"])
+	}
+}
+
+func TestSlack_Plugin_Exec_Provider_Error_Status(t *testing.T) {
+	// setup types
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	p := &Plugin{
+		Webhook:  ts.URL,
+		Env:      &Env{},
+		Provider: providerDiscord,
+		WebhookMsg: &slack.WebhookMessage{
+			Text: "hello",
+		},
+	}
+
+	err := p.Exec()
+	if err == nil {
+		t.Error("Exec should return err due to bad request status")
+	}
+}
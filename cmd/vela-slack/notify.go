@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	api "github.com/go-vela/server/api/types"
+)
+
+// options for when messages are sent.
+const (
+	notifyAlways = "always"
+	notifyChange = "change"
+)
+
+// maxBuilds is the most builds searched for the previous status.
+const maxBuilds = 50
+
+var (
+	// errSkipped is returned when the message isn't sent on purpose.
+	errSkipped = errors.New("message skipped")
+
+	// errUnchanged is returned when the message isn't sent
+	// since the build status didn't change.
+	errUnchanged = fmt.Errorf("%w: build status didn't change", errSkipped)
+)
+
+// validateDelivery validates the options for when and where messages are sent.
+func (p *Plugin) validateDelivery() error {
+	switch p.NotifyOn {
+	case "", notifyAlways:
+	case notifyChange:
+		// validate that a previous status can be found
+		if len(p.StateFile) == 0 && len(p.VelaAddr) == 0 {
+			return fmt.Errorf("no state file or Vela address provided for notify_on %s", notifyChange)
+		}
+	default:
+		return fmt.Errorf("invalid notify_on option provided: %s", p.NotifyOn)
+	}
+
+	err := p.validateQuietHours()
+	if err != nil {
+		return err
+	}
+
+	return p.validateRoutes()
+}
+
+// checkChange adds the status of the previous build on the branch to
+// the environment and returns errSkipped when the build didn't change
+// from passing to failing or back. Problems finding the previous status
+// are logged and treated as a change so the message is still sent.
+func (p *Plugin) checkChange(ctx context.Context) error {
+	if p.NotifyOn != notifyChange {
+		return nil
+	}
+
+	previous, err := p.previousStatus(ctx)
+	if err != nil {
+		logrus.Warnf("unable to find previous build status: %v", err)
+	}
+
+	p.Env.PreviousStatus = previous
+	p.Env.StatusChanged = statusChanged(previous, p.Env.BuildStatus)
+
+	if !p.Env.StatusChanged {
+		return fmt.Errorf("%w from %s", errUnchanged, previous)
+	}
+
+	return nil
+}
+
+// previousStatus returns the status of the previous build on the branch
+// from the state file or the Vela API.
+func (p *Plugin) previousStatus(ctx context.Context) (string, error) {
+	if len(p.StateFile) == 0 {
+		return p.previousBuildStatus(ctx)
+	}
+
+	state, err := readState(workspacePath(p.Env.BuildWorkspace, p.StateFile))
+	if err != nil {
+		return "", err
+	}
+
+	return state[p.stateKey()], nil
+}
+
+// recordStatus stores the status of the build in the state file. It's
+// only called once the message was sent, or wasn't needed since the
+// status didn't change, so a change is never lost to a failed delivery.
+func (p *Plugin) recordStatus() {
+	if p.NotifyOn != notifyChange || len(p.StateFile) == 0 {
+		return
+	}
+
+	path := workspacePath(p.Env.BuildWorkspace, p.StateFile)
+
+	state, err := readState(path)
+	if err != nil {
+		logrus.Warnf("unable to record build status: %v", err)
+
+		return
+	}
+
+	state[p.stateKey()] = passFail(p.Env.BuildStatus)
+
+	err = writeState(path, state)
+	if err != nil {
+		logrus.Warnf("unable to record build status: %v", err)
+	}
+}
+
+// stateKey returns the key of the branch in the state file.
+func (p *Plugin) stateKey() string {
+	return p.Env.RepositoryFullName + "@" + p.Env.BuildBranch
+}
+
+// previousBuildStatus returns the status of the most recent earlier
+// build on the branch that finished from the Vela API.
+func (p *Plugin) previousBuildStatus(ctx context.Context) (string, error) {
+	path := fmt.Sprintf(
+		"/api/v1/repos/%s/%s/builds",
+		url.PathEscape(p.Env.RepositoryOrg), url.PathEscape(p.Env.RepositoryName),
+	)
+
+	query := url.Values{
+		"branch":   {p.Env.BuildBranch},
+		"per_page": {fmt.Sprint(maxBuilds)},
+	}
+
+	var builds []api.Build
+
+	err := p.velaGet(ctx, path, query, &builds)
+	if err != nil {
+		return "", err
+	}
+
+	for _, b := range builds {
+		if b.GetNumber() >= p.Env.BuildNumber {
+			continue
+		}
+
+		switch b.GetStatus() {
+		case "success", "failure", "error", "killed":
+			return b.GetStatus(), nil
+		}
+	}
+
+	return "", nil
+}
+
+// statusChanged returns whether the build went from passing to failing
+// or back. Builds without a previous status are always a change.
+func statusChanged(previous, current string) bool {
+	if len(previous) == 0 {
+		return true
+	}
+
+	return isFailure(previous) != isFailure(current)
+}
+
+// passFail returns success or failure for the status since Vela
+// reports a build that is still passing as running to its steps.
+func passFail(status string) string {
+	if isFailure(status) {
+		return "failure"
+	}
+
+	return "success"
+}
+
+// readState reads the statuses of the branches from the state file.
+// A missing state file has no statuses.
+func readState(path string) (map[string]string, error) {
+	state := make(map[string]string)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+
+	if err != nil {
+		return state, fmt.Errorf("unable to read state file %s: %w", path, err)
+	}
+
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		return make(map[string]string), fmt.Errorf("unable to parse state file %s: %w", path, err)
+	}
+
+	return state, nil
+}
+
+// writeState writes the statuses of the branches to the state file.
+func writeState(path string, state map[string]string) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal state: %w", err)
+	}
+
+	err = os.WriteFile(path, data, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to write state file %s: %w", path, err)
+	}
+
+	return nil
+}
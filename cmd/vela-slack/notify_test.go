@@ -0,0 +1,321 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_statusChanged(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		previous string
+		current  string
+		want     bool
+	}{
+		{previous: "", current: "success", want: true},
+		{previous: "", current: "failure", want: true},
+		{previous: "success", current: "success", want: false},
+		{previous: "failure", current: "failure", want: false},
+		{previous: "failure", current: "error", want: false},
+		{previous: "success", current: "failure", want: true},
+		{previous: "error", current: "success", want: true},
+		{previous: "success", current: "running", want: false},
+		{previous: "running", current: "success", want: false},
+		{previous: "failure", current: "running", want: true},
+		{previous: "running", current: "failure", want: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := statusChanged(test.previous, test.current)
+
+		if got != test.want {
+			t.Errorf("statusChanged for %q to %q is %v, want %v", test.previous, test.current, got, test.want)
+		}
+	}
+}
+
+func TestSlack_Plugin_Validate_NotifyOn(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		notifyOn  string
+		stateFile string
+		velaAddr  string
+		failure   bool
+	}{
+		{notifyOn: notifyAlways},
+		{notifyOn: notifyChange, stateFile: "/vela/state/slack.json"},
+		{notifyOn: notifyChange, velaAddr: "https://vela.example.com"},
+		{notifyOn: notifyChange, failure: true},
+		{notifyOn: "sometimes", failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		p := &Plugin{
+			Webhook:   "https://hooks.slack.com/services/",
+			NotifyOn:  test.notifyOn,
+			StateFile: test.stateFile,
+			VelaAddr:  test.velaAddr,
+			WebhookMsg: &slack.WebhookMessage{
+				Text: "hello",
+			},
+		}
+
+		err := p.Validate()
+
+		if test.failure && err == nil {
+			t.Errorf("Validate should have returned err for %+v", test)
+		}
+
+		if !test.failure && err != nil {
+			t.Errorf("Validate returned err for %+v: %v", test, err)
+		}
+	}
+}
+
+func TestSlack_Plugin_Exec_NotifyOn_StateFile(t *testing.T) {
+	// setup types
+	var posted []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slack.WebhookMessage
+
+		err := json.NewDecoder(r.Body).Decode(&msg)
+		if err != nil {
+			t.Errorf("Decode error: %v", err)
+		}
+
+		posted = append(posted, msg.Text)
+	}))
+	defer ts.Close()
+
+	state := filepath.Join(t.TempDir(), "state.json")
+
+	// setup tests
+	tests := []struct {
+		status string
+		want   []string
+	}{
+		{status: "success", want: []string{"success after "}},
+		{status: "success", want: []string{"success after "}},
+		{status: "failure", want: []string{"success after ", "failure after success"}},
+		{status: "failure", want: []string{"success after ", "failure after success"}},
+		{status: "success", want: []string{"success after ", "failure after success", "success after failure"}},
+		{status: "running", want: []string{"success after ", "failure after success", "success after failure"}},
+		{status: "error", want: []string{"success after ", "failure after success", "success after failure", "error after success"}},
+	}
+
+	// run tests
+	for _, test := range tests {
+		p := &Plugin{
+			Webhook:   ts.URL,
+			NotifyOn:  notifyChange,
+			StateFile: state,
+			Env: &Env{
+				BuildBranch:        "main",
+				BuildStatus:        test.status,
+				RepositoryFullName: "octocat/hello-world",
+			},
+			WebhookMsg: &slack.WebhookMessage{
+				Text: "{{ .BuildStatus }} after {{ .PreviousStatus }}",
+			},
+		}
+
+		err := p.Exec()
+		if err != nil {
+			t.Errorf("Exec returned err: %v", err)
+		}
+
+		if fmt.Sprint(posted) != fmt.Sprint(test.want) {
+			t.Errorf("Exec posted %q, want %q", posted, test.want)
+		}
+	}
+
+	data, err := os.ReadFile(state)
+	if err != nil {
+		t.Errorf("ReadFile returned err: %v", err)
+	}
+
+	var got map[string]string
+
+	err = json.Unmarshal(data, &got)
+	if err != nil {
+		t.Errorf("Unmarshal returned err: %v", err)
+	}
+
+	if got["octocat/hello-world@main"] != "failure" {
+		t.Errorf("state is %v, want failure for octocat/hello-world@main", got)
+	}
+}
+
+func TestSlack_Plugin_previousBuildStatus(t *testing.T) {
+	// setup types
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/octocat/hello-world/builds" || r.URL.Query().Get("branch") != "main" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		fmt.Fprint(w, `[
+			{"number": 6, "status": "success"},
+			{"number": 5, "status": "running"},
+			{"number": 4, "status": "canceled"},
+			{"number": 3, "status": "failure"},
+			{"number": 2, "status": "success"}
+		]`)
+	}))
+	defer ts.Close()
+
+	// setup tests
+	tests := []struct {
+		number int
+		want   string
+	}{
+		{number: 7, want: "success"},
+		{number: 6, want: "failure"},
+		{number: 3, want: "success"},
+		{number: 2, want: ""},
+	}
+
+	// run tests
+	for _, test := range tests {
+		p := &Plugin{
+			VelaAddr:  ts.URL,
+			VelaToken: "vela_token",
+			Env: &Env{
+				BuildBranch:    "main",
+				BuildNumber:    test.number,
+				RepositoryOrg:  "octocat",
+				RepositoryName: "hello-world",
+			},
+		}
+
+		got, err := p.previousBuildStatus(context.Background())
+		if err != nil {
+			t.Errorf("previousBuildStatus returned err for build %d: %v", test.number, err)
+		}
+
+		if got != test.want {
+			t.Errorf("previousBuildStatus for build %d is %q, want %q", test.number, got, test.want)
+		}
+	}
+}
+
+func TestSlack_Plugin_Exec_NotifyOn_ResultFile(t *testing.T) {
+	// setup types
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("message should have been skipped")
+	}))
+	defer ts.Close()
+
+	workspace := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(workspace, "state.json"), []byte(`{"octocat/hello-world@main": "failure"}`), 0o600)
+	if err != nil {
+		t.Errorf("WriteFile returned err: %v", err)
+	}
+
+	p := &Plugin{
+		Webhook:    ts.URL,
+		NotifyOn:   notifyChange,
+		StateFile:  "state.json",
+		ResultFile: "result.json",
+		Env: &Env{
+			BuildBranch:        "main",
+			BuildStatus:        "error",
+			BuildWorkspace:     workspace,
+			RepositoryFullName: "octocat/hello-world",
+		},
+		WebhookMsg: &slack.WebhookMessage{
+			Text: "hello",
+		},
+	}
+
+	// run test
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(workspace, "result.json"))
+	if err != nil {
+		t.Errorf("ReadFile returned err: %v", err)
+	}
+
+	var got result
+
+	err = json.Unmarshal(data, &got)
+	if err != nil {
+		t.Errorf("Unmarshal returned err: %v", err)
+	}
+
+	if got.Status != "skipped" || len(got.Reason) == 0 || got.ExitCode != 0 {
+		t.Errorf("result is %+v, want skipped with a reason", got)
+	}
+}
+
+func TestSlack_Plugin_Exec_NotifyOn_Not_Delivered(t *testing.T) {
+	// setup types
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no_service", http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// setup tests
+	tests := []struct {
+		name       string
+		quietHours []string
+	}{
+		{name: "delivery error"},
+		{name: "quiet hours", quietHours: []string{"00:00-24:00"}},
+	}
+
+	// run tests
+	for _, test := range tests {
+		state := filepath.Join(t.TempDir(), "state.json")
+
+		err := os.WriteFile(state, []byte(`{"octocat/hello-world@main": "failure"}`), 0o600)
+		if err != nil {
+			t.Errorf("WriteFile returned err: %v", err)
+		}
+
+		p := &Plugin{
+			Webhook:    ts.URL,
+			NotifyOn:   notifyChange,
+			StateFile:  state,
+			QuietHours: test.quietHours,
+			Env: &Env{
+				BuildBranch:        "main",
+				BuildStatus:        "success",
+				RepositoryFullName: "octocat/hello-world",
+			},
+			WebhookMsg: &slack.WebhookMessage{
+				Text: "fixed",
+			},
+		}
+
+		_ = p.Exec()
+
+		got, err := readState(state)
+		if err != nil {
+			t.Errorf("readState returned err for %s: %v", test.name, err)
+		}
+
+		// the change is sent again by the next build
+		if got["octocat/hello-world@main"] != "failure" {
+			t.Errorf("state for %s is %v, want failure for octocat/hello-world@main", test.name, got)
+		}
+	}
+}
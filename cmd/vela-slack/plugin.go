@@ -6,21 +6,31 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
-	"github.com/Masterminds/sprig/v3"
 	"github.com/sirupsen/logrus"
 	"github.com/slack-go/slack"
+	"golang.org/x/oauth2"
 
 	registry "github.com/go-vela/server/compiler/registry/github"
 )
 
+// modes for delivering the notification.
+const (
+	modePost     = "post"
+	modeReaction = "reaction"
+	modeCancel   = "cancel"
+)
+
 type (
 	// Plugin struct represents fields user can present to plugin.
 	Plugin struct {
@@ -30,6 +40,94 @@ type (
 		Path       string
 		WebhookMsg *slack.WebhookMessage
 		Remote     bool
+		// post a plain-text message when the template fails
+		FallbackOnError bool
+		// succeed when the notification can't be delivered
+		ContinueOnError bool
+		// fail on template errors when continuing on errors
+		FailOnTemplateError bool
+		// bot token for the Slack Web API
+		BotToken string
+		// url for the Slack Web API
+		APIURL string
+		// how to handle messages exceeding the Slack limits
+		Overflow string
+		// file patterns to upload with the message
+		Files []string
+		// share the files into the message thread
+		FilesThread bool
+		// log file to include the last lines of
+		LogFile string
+		// number of lines to include from the log file
+		LogLines int
+		// JUnit XML report patterns to summarize
+		JUnit []string
+		// how to deliver the notification
+		Mode string
+		// timestamp of the message to react to
+		MessageTS string
+		// file storing the posted message for later steps
+		TSFile string
+		// file to write the outcome of the plugin to
+		ResultFile string
+		// emoji reactions to add to the message
+		ReactionsAdd []string
+		// emoji reactions to remove from the message
+		ReactionsRemove []string
+		// time to schedule the message for
+		PostAt string
+		// id of the scheduled message to cancel
+		ScheduledMessageID string
+		// backend to deliver the message with
+		Provider string
+		// send the message as a direct message to the build author
+		DMAuthor bool
+		// user to send the message to as an ephemeral message
+		EphemeralUser string
+		// http method for the webhook provider
+		WebhookMethod string
+		// headers for the webhook provider
+		WebhookHeaders []string
+		// body template for the webhook provider
+		WebhookBody string
+		// client for outbound HTTP requests
+		HTTPClient *http.Client
+		// github token for pulling remote templates
+		Token string
+		// replacer removing secret values from the message
+		Redactor *strings.Replacer
+		// add the commits since the previous successful build
+		Changelog bool
+		// ref to create the changelog from
+		ChangelogBase string
+		// maximum number of commits in the changelog
+		ChangelogLimit int
+		// add the steps of the build from the Vela API
+		VelaAPI bool
+		// address of the Vela server
+		VelaAddr string
+		// token for the Vela API
+		VelaToken string
+		// when to send the message
+		NotifyOn string
+		// file storing the status of previous builds
+		StateFile string
+		// ranges of time non-failure messages aren't sent during
+		QuietHours []string
+		// timezone of the quiet hours
+		QuietTimezone string
+		// channel messages are rerouted to during the quiet hours
+		QuietChannel string
+		// webhook messages are rerouted to during the quiet hours
+		QuietWebhook string
+		// rules sending messages for matching builds to channels
+		Routes []Route
+		// whether the first or all matching routes are used
+		RouteMatch string
+		// channels messages are allowed to be sent to
+		AllowedChannels []string
+		// webhook and api hosts messages are allowed to be sent to
+		AllowedHosts []string
 	}
 
 	// Env struct represents the environment variables the Vela injects
@@ -50,10 +148,12 @@ type (
 		BuildMessage              string
 		BuildNumber               int
 		BuildParent               int
+		BuildPullRequest          int
 		BuildRef                  string
 		BuildSender               string
 		BuildStarted              int
 		BuildSource               string
+		BuildStatus               string
 		BuildTag                  string
 		BuildTitle                string
 		BuildWorkspace            string
@@ -76,23 +176,243 @@ type (
 		RepoTimeout               int
 		RepositoryTrusted         string
 		RepoTrusted               string
-		Token                     string
+		LogTail                   string
+		Tests                     TestSummary
+		PullRequestSource         string
+		PullRequestTarget         string
+		PullRequestTitle          string
+		PullRequestLink           string
+		Vars                      map[string]string
+		Deployment                Deployment
+		Commits                   []Commit
+		Steps                     []Step
+		PreviousStatus            string
+		StatusChanged             bool
 	}
 )
 
 // Exec formats and runs the commands for sending a message via Slack.
 func (p *Plugin) Exec() error {
+	logrus.Debug("running plugin with provided configuration")
+
+	refs, err := p.exec(context.Background())
+
+	// remember the status once the message was sent or wasn't needed
+	if err == nil || errors.Is(err, errUnchanged) {
+		p.recordStatus()
+	}
+
+	// messages skipped on purpose don't fail the build
+	if errors.Is(err, errSkipped) {
+		logrus.Infof("Skipping message: %v", err)
+
+		p.writeResult(nil, err, false)
+
+		return nil
+	}
+
+	// notification failures don't fail the build when continuing on errors
+	continued := p.continueOnError(err)
+
+	// record the outcome for wrapper pipelines
+	p.writeResult(refs, err, continued)
+
+	if continued {
+		logrus.Warnf("Continuing after notification error: %v", err)
+
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	logrus.Info("Plugin finished...")
+
+	return nil
+}
+
+// exec sends the notification and returns the posted message when known.
+func (p *Plugin) exec(ctx context.Context) ([]messageRef, error) {
+	// act on a previous message instead of posting
+	switch p.Mode {
+	case modeReaction:
+		return nil, classify(classDelivery, p.react(ctx))
+	case modeCancel:
+		return nil, classify(classDelivery, p.cancelSchedule(ctx))
+	}
+
+	// only send the message when the build status changed
+	err := p.checkChange(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// hold back or reroute non-failure messages during the quiet hours
+	err = p.checkQuietHours(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// send the rendered body instead of a chat message
+	if p.Provider == providerWebhook {
+		return nil, p.sendWebhook(ctx)
+	}
+
+	msg, err := p.message()
+	if err != nil {
+		// send a minimal message so the build result isn't lost
+		if p.FallbackOnError {
+			p.postFallback(ctx, err)
+		}
+
+		return nil, classify(classTemplate, err)
+	}
+
+	// remove secret values that were rendered into the message
+	err = p.redactMessage(msg)
+	if err != nil {
+		return nil, classify(classTemplate, err)
+	}
+
+	// find the users for direct and ephemeral messages
+	if p.DMAuthor || len(p.EphemeralUser) > 0 {
+		err = p.resolveRecipients(ctx, msg)
+		if err != nil {
+			return nil, classify(classDelivery, err)
+		}
+	}
+
+	// send the message to the channels of the matching routes
+	channels := p.routeChannels(msg.Channel)
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("%w: no route matched and no channel provided", errSkipped)
+	}
+
+	refs := make([]messageRef, 0, len(channels))
+
+	for i, channel := range channels {
+		// validate the rendered channel right before sending
+		err = p.checkChannel(channel)
+		if err != nil {
+			return refs, classify(classConfig, err)
+		}
+
+		// copy the message so each channel gets its own
+		m := *msg
+		m.Channel = channel
+
+		if i > 0 {
+			logrus.Infof("Sending message to route channel %s", channel)
+		}
+
+		ref, err := p.deliver(ctx, &m)
+		if ref != nil {
+			refs = append(refs, *ref)
+		}
+
+		if err != nil {
+			return refs, err
+		}
+	}
+
+	// store the first posted message for later steps
+	ref := refs[0]
+
+	if len(p.TSFile) > 0 && (len(ref.Timestamp) > 0 || len(ref.ScheduledMessageID) > 0) {
+		err = writeMessageRef(workspacePath(p.Env.BuildWorkspace, p.TSFile), &ref)
+		if err != nil {
+			return refs, err
+		}
+	}
+
+	return refs, nil
+}
+
+// deliver posts or schedules the message and uploads the files to its channel.
+func (p *Plugin) deliver(ctx context.Context, msg *slack.WebhookMessage) (*messageRef, error) {
+	var (
+		ref    *messageRef
+		thread string
+		err    error
+	)
+
+	if len(p.PostAt) > 0 {
+		ref, err = p.schedule(ctx, msg)
+	} else {
+		ref, thread, err = p.postMessages(ctx, msg)
+	}
+
+	if err != nil {
+		return nil, classify(classDelivery, err)
+	}
+
+	if len(p.Files) > 0 {
+		if !p.FilesThread {
+			thread = ""
+		}
+
+		err = p.uploadFiles(ctx, ref.Channel, thread)
+		if err != nil {
+			return ref, classify(classDelivery, err)
+		}
+	}
+
+	return ref, nil
+}
+
+// postMessages posts the message, splitting it into follow-up messages
+// when enabled. The first posted message and its thread are returned.
+func (p *Plugin) postMessages(ctx context.Context, msg *slack.WebhookMessage) (*messageRef, string, error) {
+	msgs := []*slack.WebhookMessage{msg}
+
+	if p.Overflow == overflowSplit {
+		msgs = splitMessage(msg)
+	}
+
+	var ref *messageRef
+
+	// follow-up messages are threaded under the first message
+	thread := msg.ThreadTimestamp
+
+	for i, m := range msgs {
+		enforceLimits(m, p.Env.BuildLink)
+
+		if i > 0 {
+			m.ThreadTimestamp = thread
+		}
+
+		channel, ts, err := p.post(ctx, m)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if ref == nil {
+			ref = &messageRef{Channel: channel, Timestamp: ts}
+		}
+
+		if len(thread) == 0 {
+			thread = ts
+		}
+	}
+
+	if len(msgs) > 1 && len(thread) == 0 {
+		logrus.Warn("Posted follow-up messages outside of a thread, provide a bot token or thread_ts to thread them")
+	}
+
+	return ref, thread, nil
+}
+
+// message builds the webhook message by loading the attachment
+// file and executing the template against the environment.
+func (p *Plugin) message() (*slack.WebhookMessage, error) {
 	var (
 		attachments []slack.Attachment
 		err         error
 	)
 
-	logrus.Debug("running plugin with provided configuration")
-
-	// clean up newlines that could invalidate JSON
-	// BuildMessage is the only field that can have newlines;
-	// typically when the commit contains a title and body message
-	p.Env.BuildMessage = cleanBuildMessage(p.Env.BuildMessage)
+	// add the build context used by the template
+	p.loadEnv()
 
 	// create message struct file Slack
 	msg := slack.WebhookMessage{
@@ -116,7 +436,7 @@ func (p *Plugin) Exec() error {
 		}
 
 		if err != nil {
-			return fmt.Errorf("unable to parse attachment file: %w", err)
+			return nil, fmt.Errorf("unable to parse attachment file: %w", err)
 		}
 
 		msg.Attachments = append(msg.Attachments, attachments...)
@@ -126,7 +446,7 @@ func (p *Plugin) Exec() error {
 
 	b, err := json.Marshal(msg)
 	if err != nil {
-		return fmt.Errorf("unable to marshal webhook message: %w", err)
+		return nil, fmt.Errorf("unable to marshal webhook message: %w", err)
 	}
 
 	// for sprig, regex to remove backslashes added when buffer compiles escaped quotes `\"` as `\\\"`
@@ -137,12 +457,12 @@ func (p *Plugin) Exec() error {
 
 	r1, err := regexp.Compile("{{.*?(\\\\\").*?(\\\\\").*?}}")
 	if err != nil {
-		return fmt.Errorf("unable to execute primary regex: %w", err)
+		return nil, fmt.Errorf("unable to execute primary regex: %w", err)
 	}
 
 	r2, err := regexp.Compile("(\\\\\")")
 	if err != nil {
-		return fmt.Errorf("unable to execute secondary regex: %w", err)
+		return nil, fmt.Errorf("unable to execute secondary regex: %w", err)
 	}
 
 	bStr = r1.ReplaceAllStringFunc(bStr, func(m string) string {
@@ -153,11 +473,11 @@ func (p *Plugin) Exec() error {
 
 	logrus.Info("Parse webhook message payload...")
 
-	tmpl := template.New("slackmessage").Funcs(sprig.TxtFuncMap())
+	tmpl := template.New("slackmessage").Funcs(templateFuncs(p.Env.Vars))
 
 	tmpl, err = tmpl.Parse(string(b))
 	if err != nil {
-		return fmt.Errorf("unable to parse from webhook message: %w", err)
+		return nil, fmt.Errorf("unable to parse from webhook message: %w", err)
 	}
 
 	logrus.Info("Execute template conversion on webhook message...")
@@ -166,26 +486,76 @@ func (p *Plugin) Exec() error {
 
 	err = tmpl.Execute(buffer, p.Env)
 	if err != nil {
-		return fmt.Errorf("unable to execute template on webhook message: %w", err)
+		return nil, fmt.Errorf("unable to execute template on webhook message: %w", err)
 	}
 
 	logrus.Info("Unmarshal bytes to webhook message...")
 
 	err = json.Unmarshal(buffer.Bytes(), &msg)
 	if err != nil {
-		return fmt.Errorf("unable to unmarshal webhook message: %w", err)
+		return nil, fmt.Errorf("unable to unmarshal webhook message: %w", err)
 	}
 
-	logrus.Info("Posting webhook message...")
+	return &msg, nil
+}
+
+// loadEnv cleans the environment and adds the optional build
+// context read from the workspace, GitHub and the Vela API.
+func (p *Plugin) loadEnv() {
+	// clean up newlines that could invalidate JSON
+	// BuildMessage is the only field that can have newlines;
+	// typically when the commit contains a title and body message
+	p.Env.BuildMessage = cleanBuildMessage(p.Env.BuildMessage)
+
+	// read the log file and test reports from the workspace
+	p.loadWorkspace()
+
+	// add the commits since the previous successful build
+	p.loadChangelog()
+
+	// add the steps of the build from the Vela API
+	p.loadSteps()
+
+	// add the task of the deployment from the Vela API
+	p.loadDeploymentTask()
+}
+
+// postFallback sends a plain-text message built from the
+// environment when the message template could not be used.
+func (p *Plugin) postFallback(ctx context.Context, cause error) {
+	logrus.Warnf("Posting fallback message due to template error: %v", cause)
+
+	msg := &slack.WebhookMessage{
+		Username:        p.WebhookMsg.Username,
+		IconEmoji:       p.WebhookMsg.IconEmoji,
+		IconURL:         p.WebhookMsg.IconURL,
+		Channel:         p.WebhookMsg.Channel,
+		ThreadTimestamp: p.WebhookMsg.ThreadTimestamp,
+		Text:            fallbackText(p.Env),
+	}
 
-	err = slack.PostWebhook(p.Webhook, &msg)
+	_, _, err := p.post(ctx, msg)
 	if err != nil {
-		return fmt.Errorf("unable to post webhook message: %w", err)
+		logrus.Errorf("unable to post fallback message: %v", err)
 	}
+}
 
-	logrus.Info("Plugin finished...")
+// fallbackText creates a minimal plain-text summary of the build.
+func fallbackText(e *Env) string {
+	build := fmt.Sprintf("%s build #%d", e.RepositoryFullName, e.BuildNumber)
+	if len(e.BuildLink) > 0 {
+		build = fmt.Sprintf("<%s|%s>", e.BuildLink, build)
+	}
 
-	return nil
+	status := e.BuildStatus
+	if len(status) == 0 {
+		status = "unknown"
+	}
+
+	return fmt.Sprintf(
+		"%s finished with status: %s\n_Unable to render the Slack message template, check the step logs for details._",
+		build, status,
+	)
 }
 
 func cleanBuildMessage(buildMessage string) string {
@@ -200,9 +570,89 @@ func cleanBuildMessage(buildMessage string) string {
 func (p *Plugin) Validate() error {
 	logrus.Debug("validating plugin configuration")
 
-	// validate that a webhook was supplied
-	if len(p.Webhook) == 0 {
-		return fmt.Errorf("no webhook provided")
+	// validate that a webhook or bot token was supplied
+	if len(p.Webhook) == 0 && len(p.BotToken) == 0 {
+		return fmt.Errorf("no webhook or bot token provided")
+	}
+
+	// validate the provider
+	err := p.validateProvider()
+	if err != nil {
+		return err
+	}
+
+	// validate the webhook and channel are allowed
+	err = p.validateAllowlist()
+	if err != nil {
+		return err
+	}
+
+	// validate the options for when messages are sent
+	err = p.validateDelivery()
+	if err != nil {
+		return err
+	}
+
+	// validate the configuration for acting on a previous message
+	switch p.Mode {
+	case modeReaction:
+		return p.validateReaction()
+	case modeCancel:
+		return p.validateCancel()
+	}
+
+	// validate the configuration for sending the body template
+	if p.Provider == providerWebhook {
+		return p.validateWebhook()
+	}
+
+	// validate that a channel was supplied for the Slack API
+	if p.usesAPI() && !p.hasChannel() {
+		return fmt.Errorf("no channel provided for bot token")
+	}
+
+	// validate that a bot token was supplied for direct and ephemeral messages
+	if (p.DMAuthor || len(p.EphemeralUser) > 0) && !p.usesAPI() {
+		return fmt.Errorf("no bot token provided for direct or ephemeral messages")
+	}
+
+	// validate that only one of direct or ephemeral messages was requested
+	if p.DMAuthor && len(p.EphemeralUser) > 0 {
+		return fmt.Errorf("unable to send a direct message and an ephemeral message")
+	}
+
+	// validate that ephemeral messages aren't scheduled
+	if len(p.EphemeralUser) > 0 && len(p.PostAt) > 0 {
+		return fmt.Errorf("unable to schedule an ephemeral message")
+	}
+
+	// validate that a bot token was supplied for uploading files
+	if len(p.Files) > 0 && !p.usesAPI() {
+		return fmt.Errorf("no bot token provided for uploading files")
+	}
+
+	// validate that a bot token was supplied for scheduling
+	if len(p.PostAt) > 0 && !p.usesAPI() {
+		return fmt.Errorf("no bot token provided for scheduling messages")
+	}
+
+	// validate that files aren't uploaded before the scheduled message
+	if len(p.PostAt) > 0 && len(p.Files) > 0 {
+		return fmt.Errorf("unable to upload files with a scheduled message")
+	}
+
+	// validate the mode option
+	switch p.Mode {
+	case "", modePost:
+	default:
+		return fmt.Errorf("invalid mode provided: %s", p.Mode)
+	}
+
+	// validate the overflow option
+	switch p.Overflow {
+	case "", overflowTruncate, overflowSplit:
+	default:
+		return fmt.Errorf("invalid overflow option provided: %s", p.Overflow)
 	}
 
 	// validate that a message was defined or
@@ -264,22 +714,44 @@ func getAttachmentFromFile(p *Plugin) ([]slack.Attachment, error) {
 // getRemoteAttachment function to open and parse slack attachment json file into
 // slack webhook message payload.
 func getRemoteAttachment(p *Plugin) ([]slack.Attachment, error) {
-	var (
-		bytes []byte
-		err   error
-	)
+	bytes, err := p.fetchTemplate(p.Path)
+	if err != nil {
+		return nil, err
+	}
 
-	ctx := context.Background()
+	bytes = replaceString(bytes, p)
 
-	reg, err := registry.New(ctx, p.Env.RegistryURL, p.Env.Token)
+	// create a variable to hold our message
+	var msg slack.WebhookMessage
+
+	// cast bytes to go struct
+	err = json.Unmarshal(bytes, &msg)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("unable to unmarshal json file: %w", err)
 	}
 
-	// parse source from slack attachment
-	src, err := reg.Parse(p.Path)
+	return msg.Attachments, err
+}
+
+// fetchTemplate pulls the file from the source with the registry client.
+func (p *Plugin) fetchTemplate(source string) ([]byte, error) {
+	// use the configured client for the authenticated GitHub client
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, p.httpClient())
+
+	reg, err := registry.New(ctx, p.Env.RegistryURL, p.Token)
 	if err != nil {
-		return nil, fmt.Errorf("invalid slack attachment source provided: %w", err)
+		return nil, classify(classRemote, err)
+	}
+
+	// the unauthenticated GitHub client is created without the context
+	if len(p.Token) == 0 {
+		reg.Github = p.unauthenticatedGitHub(reg.Github.BaseURL)
+	}
+
+	// parse the source of the template
+	src, err := reg.Parse(source)
+	if err != nil {
+		return nil, classify(classConfig, fmt.Errorf("invalid template source provided: %s: %w", source, err))
 	}
 
 	logrus.WithFields(logrus.Fields{
@@ -289,22 +761,19 @@ func getRemoteAttachment(p *Plugin) ([]slack.Attachment, error) {
 		"host": src.Host,
 	}).Tracef("Using authenticated GitHub client to pull template")
 
-	// use private (authenticated) github instance to pull from
-	bytes, err = reg.Template(ctx, nil, src)
-	if err != nil {
-		return nil, err
-	}
-
-	bytes = replaceString(bytes, p)
+	start := time.Now()
 
-	// create a variable to hold our message
-	var msg slack.WebhookMessage
+	// use private (authenticated) github instance to pull from
+	bytes, err := reg.Template(ctx, nil, src)
 
-	// cast bytes to go struct
-	err = json.Unmarshal(bytes, &msg)
+	fields := deliveryFields(src.Host, 1, start)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal json file: %w", err)
+		logrus.WithFields(fields).Warn("Unable to fetch remote template")
+
+		return nil, classify(classRemote, err)
 	}
 
-	return msg.Attachments, err
+	logrus.WithFields(fields).Debug("Fetched remote template")
+
+	return bytes, nil
 }
@@ -3,10 +3,12 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/slack-go/slack"
@@ -451,3 +453,196 @@ func TestSlack_Plugin_Exec_Do_Not_Remove_Escape_Chara(t *testing.T) {
 		t.Errorf("Exec returned err: %v", err)
 	}
 }
+
+func TestSlack_Plugin_Exec_Fallback_On_Error(t *testing.T) {
+	// setup types
+	var posted slack.WebhookMessage
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&posted)
+		if err != nil {
+			t.Errorf("Decode error: %v", err)
+		}
+
+		fmt.Fprintln(w, "ok")
+	}))
+	defer ts.Close()
+
+	p := &Plugin{
+		Webhook: ts.URL,
+		Env: &Env{
+			BuildLink:          "https://vela.example.com/octocat/hello-world/1",
+			BuildNumber:        1,
+			BuildStatus:        "failure",
+			RepositoryFullName: "octocat/hello-world",
+		},
+		Path:            "testdata/slack_attachment_bad.json",
+		WebhookMsg:      &slack.WebhookMessage{},
+		Remote:          false,
+		FallbackOnError: true,
+	}
+
+	err := p.Exec()
+	if err == nil {
+		t.Error("Exec should return err due to invalid JSON file")
+	}
+
+	if !strings.Contains(posted.Text, "<https://vela.example.com/octocat/hello-world/1|octocat/hello-world build #1>") {
+		t.Errorf("Exec posted fallback text %q without build link", posted.Text)
+	}
+
+	if !strings.Contains(posted.Text, "failure") {
+		t.Errorf("Exec posted fallback text %q without build status", posted.Text)
+	}
+}
+
+func TestSlack_Plugin_Exec_No_Fallback_On_Error(t *testing.T) {
+	// setup types
+	called := false
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+
+		fmt.Fprintln(w, "ok")
+	}))
+	defer ts.Close()
+
+	p := &Plugin{
+		Webhook:    ts.URL,
+		Env:        &Env{},
+		Path:       "testdata/slack_attachment_bad.json",
+		WebhookMsg: &slack.WebhookMessage{},
+		Remote:     false,
+	}
+
+	err := p.Exec()
+	if err == nil {
+		t.Error("Exec should return err due to invalid JSON file")
+	}
+
+	if called {
+		t.Error("Exec should not post a message when fallback is disabled")
+	}
+}
+
+func TestSlack_Plugin_Exec_Slack_Funcs(t *testing.T) {
+	// setup types
+	var posted slack.WebhookMessage
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&posted)
+		if err != nil {
+			t.Errorf("Decode error: %v", err)
+		}
+
+		fmt.Fprintln(w, "ok")
+	}))
+	defer ts.Close()
+
+	p := &Plugin{
+		Webhook: ts.URL,
+		Env: &Env{
+			BuildLink:    "https://vela.example.com/octocat/hello-world/1",
+			BuildMessage: "fix <nil> pointer",
+			BuildStatus:  "success",
+		},
+		Path: "",
+		WebhookMsg: &slack.WebhookMessage{
+			Text: "{{ statusEmoji .BuildStatus }} {{ slackLink .BuildLink \"Build\" }}: {{ .BuildMessage | slackEscape }}",
+		},
+		Remote: false,
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	want := ":white_check_mark: <https://vela.example.com/octocat/hello-world/1|Build>: fix &lt;nil&gt; pointer"
+	if posted.Text != want {
+		t.Errorf("Exec posted text %q, want %q", posted.Text, want)
+	}
+}
+
+func TestSlack_Plugin_Validate_Bot_Token(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Env:      &Env{},
+		BotToken: "xoxb-token",
+		WebhookMsg: &slack.WebhookMessage{
+			Channel: "#builds",
+			Text:    "hello",
+		},
+	}
+
+	err := p.Validate()
+	if err != nil {
+		t.Errorf("Validate returned err: %v", err)
+	}
+
+	p.WebhookMsg.Channel = ""
+
+	err = p.Validate()
+	if err == nil {
+		t.Error("Validate should return err due to missing channel")
+	}
+}
+
+func TestSlack_Plugin_Validate_Bad_Overflow(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Webhook:  "webhook_url",
+		Env:      &Env{},
+		Overflow: "drop",
+		WebhookMsg: &slack.WebhookMessage{
+			Text: "hello",
+		},
+	}
+
+	err := p.Validate()
+	if err == nil {
+		t.Error("Validate should return err due to invalid overflow")
+	}
+}
+
+func TestSlack_Plugin_Exec_Split_Thread(t *testing.T) {
+	// setup types
+	var threads []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			t.Errorf("ParseForm error: %v", err)
+		}
+
+		threads = append(threads, r.PostForm.Get("thread_ts"))
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"ok": true, "channel": "C024BE91L", "ts": "1503435956.000247"}`)
+	}))
+	defer ts.Close()
+
+	p := &Plugin{
+		Env:      &Env{},
+		BotToken: "xoxb-token",
+		APIURL:   ts.URL + "/",
+		Overflow: overflowSplit,
+		WebhookMsg: &slack.WebhookMessage{
+			Channel: "#builds",
+			Text:    strings.Repeat("a", maxTextLength+1),
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	if len(threads) != 2 {
+		t.Fatalf("Exec posted %d messages, want 2", len(threads))
+	}
+
+	if threads[0] != "" || threads[1] != "1503435956.000247" {
+		t.Errorf("Exec posted thread timestamps %v", threads)
+	}
+}
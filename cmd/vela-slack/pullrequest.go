@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// eventPull is the build event for pull requests.
+const eventPull = "pull_request"
+
+// pullRequestNumber returns the pull request number, parsing
+// it from a refs/pull/<number>/head reference when not provided.
+func pullRequestNumber(number int, ref string) int {
+	if number > 0 {
+		return number
+	}
+
+	parts := strings.Split(ref, "/")
+	if len(parts) != 4 || parts[0] != "refs" || parts[1] != "pull" {
+		return 0
+	}
+
+	n, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return 0
+	}
+
+	return n
+}
+
+// pullRequestTitle returns the pull request title, which is
+// used as the build message by Vela for pull request events.
+// The title is escaped so it can be placed in the JSON message template.
+func pullRequestTitle(event, message string) string {
+	if event != eventPull {
+		return ""
+	}
+
+	title, _, _ := strings.Cut(message, "\n")
+
+	return escapeJSON(strings.TrimSpace(title))
+}
+
+// pullRequestLink returns the link to the pull request in the repository.
+func pullRequestLink(repoLink string, number int) string {
+	if number == 0 || len(repoLink) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/pull/%d", strings.TrimSuffix(repoLink, "/"), number)
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+)
+
+func TestSlack_pullRequestNumber(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		number int
+		ref    string
+		want   int
+	}{
+		{number: 42, ref: "refs/pull/7/head", want: 42},
+		{ref: "refs/pull/123/head", want: 123},
+		{ref: "refs/pull/123/merge", want: 123},
+		{ref: "refs/heads/main", want: 0},
+		{ref: "refs/pull/abc/head", want: 0},
+		{ref: "", want: 0},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := pullRequestNumber(test.number, test.ref)
+
+		if got != test.want {
+			t.Errorf("pullRequestNumber for %s is %d, want %d", test.ref, got, test.want)
+		}
+	}
+}
+
+func TestSlack_pullRequestTitle(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		event   string
+		message string
+		want    string
+	}{
+		{event: "pull_request", message: "Add \"retry\" support\n\nCloses #12", want: `Add \"retry\" support`},
+		{event: "comment", message: "/deploy", want: ""},
+		{event: "push", message: "Update README", want: ""},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := pullRequestTitle(test.event, test.message)
+
+		if got != test.want {
+			t.Errorf("pullRequestTitle for %s is %s, want %s", test.event, got, test.want)
+		}
+	}
+}
+
+func TestSlack_pullRequestLink(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		link   string
+		number int
+		want   string
+	}{
+		{link: "https://github.com/octocat/hello-world", number: 123, want: "https://github.com/octocat/hello-world/pull/123"},
+		{link: "https://github.com/octocat/hello-world/", number: 1, want: "https://github.com/octocat/hello-world/pull/1"},
+		{link: "https://github.com/octocat/hello-world", number: 0, want: ""},
+		{link: "", number: 1, want: ""},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := pullRequestLink(test.link, test.number)
+
+		if got != test.want {
+			t.Errorf("pullRequestLink for %s is %s, want %s", test.link, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// embed the timezone database for the scratch image
+	_ "time/tzdata"
+
+	"github.com/sirupsen/logrus"
+)
+
+// minutesPerDay is the end of an all day quiet range.
+const minutesPerDay = 24 * 60
+
+// quietRange represents a range of time on some weekdays
+// during which non-failure messages aren't sent.
+type quietRange struct {
+	days  [7]bool
+	start int
+	end   int
+}
+
+// contains returns whether the time is in the quiet range. Ranges ending
+// before they start continue into the next day, e.g. 18:00-08:00.
+func (r *quietRange) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+
+	if r.start <= r.end {
+		return r.days[day] && minute >= r.start && minute < r.end
+	}
+
+	// the early part of an overnight range belongs to the previous day
+	if minute < r.end {
+		return r.days[(day+6)%7]
+	}
+
+	return r.days[day] && minute >= r.start
+}
+
+// validateQuietHours validates the quiet hours and the channel and webhook
+// messages are rerouted to.
+func (p *Plugin) validateQuietHours() error {
+	if len(p.QuietHours) == 0 {
+		return nil
+	}
+
+	_, err := parseQuietHours(p.QuietHours)
+	if err != nil {
+		return err
+	}
+
+	_, err = time.LoadLocation(p.QuietTimezone)
+	if err != nil {
+		return fmt.Errorf("invalid quiet_timezone provided: %w", err)
+	}
+
+	hosts := splitList(p.AllowedHosts)
+	if len(hosts) > 0 && len(p.QuietWebhook) > 0 {
+		err = checkHost(p.QuietWebhook, hosts)
+		if err != nil {
+			return fmt.Errorf("quiet webhook not allowed: %w", err)
+		}
+	}
+
+	channels := splitList(p.AllowedChannels)
+	if len(channels) > 0 && len(p.QuietChannel) > 0 && !channelAllowed(p.QuietChannel, channels) {
+		return fmt.Errorf("quiet channel not allowed: %s", p.QuietChannel)
+	}
+
+	return nil
+}
+
+// checkQuietHours reroutes non-failure messages sent during the quiet
+// hours to the quiet channel or webhook. Without a quiet channel or
+// webhook for the provider, errSkipped is returned.
+func (p *Plugin) checkQuietHours(now time.Time) error {
+	if len(p.QuietHours) == 0 || isFailure(p.Env.BuildStatus) {
+		return nil
+	}
+
+	ranges, err := parseQuietHours(p.QuietHours)
+	if err != nil {
+		return classify(classConfig, err)
+	}
+
+	loc, err := time.LoadLocation(p.QuietTimezone)
+	if err != nil {
+		return classify(classConfig, fmt.Errorf("invalid quiet_timezone provided: %w", err))
+	}
+
+	if !inQuietHours(ranges, now.In(loc)) {
+		return nil
+	}
+
+	switch {
+	case p.usesAPI() && len(p.QuietChannel) > 0:
+		logrus.Infof("Rerouting message to %s during quiet hours", p.QuietChannel)
+
+		// the quiet channel replaces the channels of the routes
+		p.WebhookMsg.Channel = p.QuietChannel
+		p.Routes = nil
+	case !p.usesAPI() && len(p.QuietWebhook) > 0:
+		logrus.Info("Rerouting message to the quiet webhook during quiet hours")
+
+		p.Webhook = p.QuietWebhook
+	default:
+		return fmt.Errorf("%w: build status %s during quiet hours", errSkipped, p.Env.BuildStatus)
+	}
+
+	return nil
+}
+
+// inQuietHours returns whether the time is in any of the quiet ranges.
+func inQuietHours(ranges []quietRange, t time.Time) bool {
+	for i := range ranges {
+		if ranges[i].contains(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isFailure returns whether the build status is a failure
+// that is always delivered.
+func isFailure(status string) bool {
+	switch strings.ToLower(status) {
+	case "failure", "error", "killed":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseQuietHours parses quiet ranges in the `[days] [HH:MM-HH:MM]`
+// format, e.g. `Mon-Fri 18:00-08:00`, `Sat,Sun` or `22:00-07:00`.
+func parseQuietHours(values []string) ([]quietRange, error) {
+	ranges := make([]quietRange, 0, len(values))
+
+	for _, value := range values {
+		r, err := parseQuietRange(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quiet_hours provided: %s: %w", value, err)
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	return ranges, nil
+}
+
+// parseQuietRange parses a single quiet range.
+func parseQuietRange(value string) (quietRange, error) {
+	r := quietRange{end: minutesPerDay}
+
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields) > 2 {
+		return r, fmt.Errorf("expected [days] [HH:MM-HH:MM]")
+	}
+
+	// the time range is the last field and contains a colon
+	times := ""
+	if strings.Contains(fields[len(fields)-1], ":") {
+		times = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+
+	if len(fields) == 0 {
+		for i := range r.days {
+			r.days[i] = true
+		}
+	} else {
+		days, err := parseWeekdays(fields[0])
+		if err != nil {
+			return r, err
+		}
+
+		r.days = days
+	}
+
+	if len(times) == 0 {
+		return r, nil
+	}
+
+	start, end, ok := strings.Cut(times, "-")
+	if !ok {
+		return r, fmt.Errorf("expected HH:MM-HH:MM: %s", times)
+	}
+
+	var err error
+
+	r.start, err = parseClock(start)
+	if err != nil {
+		return r, err
+	}
+
+	r.end, err = parseClock(end)
+	if err != nil {
+		return r, err
+	}
+
+	if r.start == r.end {
+		return r, fmt.Errorf("empty time range: %s", times)
+	}
+
+	return r, nil
+}
+
+// parseWeekdays parses a list of weekdays or weekday ranges,
+// e.g. `Mon-Fri` or `Sat,Sun`.
+func parseWeekdays(value string) ([7]bool, error) {
+	var days [7]bool
+
+	for _, part := range strings.Split(value, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+
+		start, err := parseWeekday(first)
+		if err != nil {
+			return days, err
+		}
+
+		end := start
+
+		if isRange {
+			end, err = parseWeekday(last)
+			if err != nil {
+				return days, err
+			}
+		}
+
+		// ranges may wrap around the week, e.g. Fri-Mon
+		for d := start; ; d = (d + 1) % 7 {
+			days[d] = true
+
+			if d == end {
+				break
+			}
+		}
+	}
+
+	return days, nil
+}
+
+// parseWeekday parses the three letter abbreviation of a weekday.
+func parseWeekday(value string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(value, d.String()[:3]) {
+			return d, nil
+		}
+	}
+
+	return time.Sunday, fmt.Errorf("unknown weekday: %s", value)
+}
+
+// parseClock parses a time of day in the HH:MM format to minutes.
+// The end of the day may be given as 24:00.
+func parseClock(value string) (int, error) {
+	if value == "24:00" {
+		return minutesPerDay, nil
+	}
+
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM: %s", value)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_parseQuietHours(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		value   string
+		failure bool
+	}{
+		{value: "Mon-Fri 18:00-08:00"},
+		{value: "Sat,Sun"},
+		{value: "fri-mon"},
+		{value: "22:00-07:00"},
+		{value: "Mon 00:00-24:00"},
+		{value: "", failure: true},
+		{value: "Mon-Fri 18:00", failure: true},
+		{value: "Someday 18:00-08:00", failure: true},
+		{value: "Mon 25:00-08:00", failure: true},
+		{value: "Mon 08:00-08:00", failure: true},
+		{value: "Mon Tue 08:00-09:00", failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		_, err := parseQuietHours([]string{test.value})
+
+		if test.failure && err == nil {
+			t.Errorf("parseQuietHours should have returned err for %q", test.value)
+		}
+
+		if !test.failure && err != nil {
+			t.Errorf("parseQuietHours returned err for %q: %v", test.value, err)
+		}
+	}
+}
+
+func TestSlack_inQuietHours(t *testing.T) {
+	// setup types
+	ranges, err := parseQuietHours([]string{"Mon-Fri 18:00-08:00", "Sat,Sun"})
+	if err != nil {
+		t.Errorf("parseQuietHours returned err: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		time string
+		want bool
+	}{
+		// Monday
+		{time: "2024-01-01T07:59:00Z", want: false},
+		{time: "2024-01-01T12:00:00Z", want: false},
+		{time: "2024-01-01T18:00:00Z", want: true},
+		// Tuesday
+		{time: "2024-01-02T07:59:00Z", want: true},
+		{time: "2024-01-02T08:00:00Z", want: false},
+		// Saturday
+		{time: "2024-01-06T12:00:00Z", want: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		now, err := time.Parse(time.RFC3339, test.time)
+		if err != nil {
+			t.Errorf("Parse returned err: %v", err)
+		}
+
+		got := inQuietHours(ranges, now)
+
+		if got != test.want {
+			t.Errorf("inQuietHours for %s is %v, want %v", test.time, got, test.want)
+		}
+	}
+}
+
+func TestSlack_Plugin_checkQuietHours(t *testing.T) {
+	// setup types
+	// Monday at 20:00 in New York
+	now := time.Date(2024, time.January, 2, 1, 0, 0, 0, time.UTC)
+
+	// setup tests
+	tests := []struct {
+		name        string
+		status      string
+		botToken    string
+		channel     string
+		webhook     string
+		wantChannel string
+		wantWebhook string
+		skipped     bool
+	}{
+		{
+			name:        "failure",
+			status:      "failure",
+			botToken:    "xoxb-token",
+			channel:     "#quiet",
+			wantChannel: "#builds",
+			wantWebhook: "https://hooks.slack.com/services/builds",
+		},
+		{
+			name:        "channel",
+			status:      "success",
+			botToken:    "xoxb-token",
+			channel:     "#quiet",
+			wantChannel: "#quiet",
+			wantWebhook: "https://hooks.slack.com/services/builds",
+		},
+		{
+			name:        "webhook",
+			status:      "success",
+			webhook:     "https://hooks.slack.com/services/quiet",
+			wantChannel: "#builds",
+			wantWebhook: "https://hooks.slack.com/services/quiet",
+		},
+		{
+			name:     "suppressed",
+			status:   "success",
+			botToken: "xoxb-token",
+			webhook:  "https://hooks.slack.com/services/quiet",
+			skipped:  true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		p := &Plugin{
+			Webhook:       "https://hooks.slack.com/services/builds",
+			BotToken:      test.botToken,
+			QuietHours:    []string{"Mon-Fri 18:00-08:00"},
+			QuietTimezone: "America/New_York",
+			QuietChannel:  test.channel,
+			QuietWebhook:  test.webhook,
+			Env:           &Env{BuildStatus: test.status},
+			WebhookMsg: &slack.WebhookMessage{
+				Channel: "#builds",
+			},
+		}
+
+		err := p.checkQuietHours(now)
+
+		if test.skipped {
+			if !errors.Is(err, errSkipped) {
+				t.Errorf("checkQuietHours for %s returned %v, want skipped", test.name, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("checkQuietHours for %s returned err: %v", test.name, err)
+		}
+
+		if p.WebhookMsg.Channel != test.wantChannel || p.Webhook != test.wantWebhook {
+			t.Errorf("checkQuietHours for %s sends to %s %s, want %s %s",
+				test.name, p.WebhookMsg.Channel, p.Webhook, test.wantChannel, test.wantWebhook)
+		}
+	}
+}
+
+func TestSlack_Plugin_Validate_QuietHours(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		hours    string
+		timezone string
+		channel  string
+		failure  bool
+	}{
+		{hours: "Mon-Fri 18:00-08:00", timezone: "Europe/Berlin", channel: "#builds-quiet"},
+		{hours: "Mon-Fri 18:00-08:00", timezone: "Mars/Olympus_Mons", failure: true},
+		{hours: "Mon-Fri 18:00", failure: true},
+		{hours: "Mon-Fri 18:00-08:00", channel: "#random", failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		p := &Plugin{
+			Webhook:         "https://hooks.slack.com/services/",
+			QuietHours:      []string{test.hours},
+			QuietTimezone:   test.timezone,
+			QuietChannel:    test.channel,
+			AllowedChannels: []string{"#builds", "#builds-quiet"},
+			WebhookMsg: &slack.WebhookMessage{
+				Text: "hello",
+			},
+		}
+
+		err := p.Validate()
+
+		if test.failure && err == nil {
+			t.Errorf("Validate should have returned err for %+v", test)
+		}
+
+		if !test.failure && err != nil {
+			t.Errorf("Validate returned err for %+v: %v", test, err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// react adds and removes the emoji reactions on a previously
+// posted message instead of posting a new message.
+func (p *Plugin) react(ctx context.Context) error {
+	ref := &messageRef{
+		Channel:   p.WebhookMsg.Channel,
+		Timestamp: p.MessageTS,
+	}
+
+	// use the message stored by a previous step
+	if len(ref.Timestamp) == 0 {
+		stored, err := readMessageRef(workspacePath(p.Env.BuildWorkspace, p.TSFile))
+		if err != nil {
+			return err
+		}
+
+		ref = stored
+	}
+
+	item := slack.NewRefToMessage(ref.Channel, ref.Timestamp)
+	api := p.client()
+
+	for _, name := range p.ReactionsRemove {
+		logrus.Infof("Removing reaction %s from message %s...", name, ref.Timestamp)
+
+		err := api.RemoveReactionContext(ctx, reactionName(name), item)
+		if err != nil && !isSlackError(err, "no_reaction") {
+			return fmt.Errorf("unable to remove reaction %s: %w", name, err)
+		}
+	}
+
+	for _, name := range p.ReactionsAdd {
+		logrus.Infof("Adding reaction %s to message %s...", name, ref.Timestamp)
+
+		err := api.AddReactionContext(ctx, reactionName(name), item)
+		if err != nil && !isSlackError(err, "already_reacted") {
+			return fmt.Errorf("unable to add reaction %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// reactionName strips the colons from the emoji name.
+func reactionName(name string) string {
+	return strings.Trim(strings.TrimSpace(name), ":")
+}
+
+// isSlackError checks if the error is the Slack API error code.
+func isSlackError(err error, code string) bool {
+	var resp slack.SlackErrorResponse
+
+	return errors.As(err, &resp) && resp.Err == code
+}
+
+// validateReaction validates the configuration for the reaction mode.
+func (p *Plugin) validateReaction() error {
+	// validate that a bot token was supplied
+	if !p.usesAPI() {
+		return fmt.Errorf("no bot token provided for reactions")
+	}
+
+	// validate that a message was supplied
+	if len(p.MessageTS) == 0 && len(p.TSFile) == 0 {
+		return fmt.Errorf("no message_ts or ts_file provided for reactions")
+	}
+
+	// validate that a channel was supplied with the timestamp
+	if len(p.MessageTS) > 0 && len(p.WebhookMsg.Channel) == 0 {
+		return fmt.Errorf("no channel provided for reactions")
+	}
+
+	// validate that reactions were supplied
+	if len(p.ReactionsAdd) == 0 && len(p.ReactionsRemove) == 0 {
+		return fmt.Errorf("no reactions provided to add or remove")
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_Plugin_Exec_Reaction(t *testing.T) {
+	// setup types
+	var calls []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			t.Errorf("ParseForm error: %v", err)
+		}
+
+		calls = append(calls, fmt.Sprintf("%s %s %s %s", r.URL.Path, r.PostForm.Get("name"), r.PostForm.Get("channel"), r.PostForm.Get("timestamp")))
+
+		// the reaction was never added to the message
+		if r.URL.Path == "/reactions.remove" {
+			fmt.Fprintln(w, `{"ok": false, "error": "no_reaction"}`)
+
+			return
+		}
+
+		fmt.Fprintln(w, `{"ok": true}`)
+	}))
+	defer ts.Close()
+
+	file := filepath.Join(t.TempDir(), "slack.json")
+
+	err := writeMessageRef(file, &messageRef{Channel: "C024BE91L", Timestamp: "1503435956.000247"})
+	if err != nil {
+		t.Fatalf("writeMessageRef returned err: %v", err)
+	}
+
+	p := &Plugin{
+		Env:             &Env{},
+		BotToken:        "xoxb-token",
+		APIURL:          ts.URL + "/",
+		Mode:            modeReaction,
+		TSFile:          file,
+		ReactionsAdd:    []string{":white_check_mark:"},
+		ReactionsRemove: []string{"hourglass"},
+		WebhookMsg:      &slack.WebhookMessage{},
+	}
+
+	err = p.Validate()
+	if err != nil {
+		t.Errorf("Validate returned err: %v", err)
+	}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	want := "/reactions.remove hourglass C024BE91L 1503435956.000247|/reactions.add white_check_mark C024BE91L 1503435956.000247"
+	if strings.Join(calls, "|") != want {
+		t.Errorf("Exec called %v, want %s", calls, want)
+	}
+}
+
+func TestSlack_Plugin_Exec_Reaction_Error(t *testing.T) {
+	// setup types
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, `{"ok": false, "error": "message_not_found"}`)
+	}))
+	defer ts.Close()
+
+	p := &Plugin{
+		Env:          &Env{},
+		BotToken:     "xoxb-token",
+		APIURL:       ts.URL + "/",
+		Mode:         modeReaction,
+		MessageTS:    "1503435956.000247",
+		ReactionsAdd: []string{"white_check_mark"},
+		WebhookMsg: &slack.WebhookMessage{
+			Channel: "C024BE91L",
+		},
+	}
+
+	err := p.Exec()
+	if err == nil {
+		t.Error("Exec should return err due to missing message")
+	}
+}
+
+func TestSlack_Plugin_Exec_Write_TS_File(t *testing.T) {
+	// setup types
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, `{"ok": true, "channel": "C024BE91L", "ts": "1503435956.000247"}`)
+	}))
+	defer ts.Close()
+
+	file := filepath.Join(t.TempDir(), "slack.json")
+
+	p := &Plugin{
+		Env:      &Env{},
+		BotToken: "xoxb-token",
+		APIURL:   ts.URL + "/",
+		TSFile:   file,
+		WebhookMsg: &slack.WebhookMessage{
+			Channel: "#builds",
+			Text:    "hello",
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	got, err := readMessageRef(file)
+	if err != nil {
+		t.Errorf("readMessageRef returned err: %v", err)
+	}
+
+	if got.Channel != "C024BE91L" || got.Timestamp != "1503435956.000247" {
+		t.Errorf("Exec stored message %v", got)
+	}
+}
+
+func TestSlack_Plugin_Validate_Reaction(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		p    *Plugin
+	}{
+		{
+			name: "missing bot token",
+			p:    &Plugin{Mode: modeReaction, TSFile: "slack.json", ReactionsAdd: []string{"tada"}},
+		},
+		{
+			name: "missing message",
+			p:    &Plugin{Mode: modeReaction, BotToken: "xoxb-token", ReactionsAdd: []string{"tada"}},
+		},
+		{
+			name: "missing channel",
+			p:    &Plugin{Mode: modeReaction, BotToken: "xoxb-token", MessageTS: "1503435956.000247", ReactionsAdd: []string{"tada"}},
+		},
+		{
+			name: "missing reactions",
+			p:    &Plugin{Mode: modeReaction, BotToken: "xoxb-token", TSFile: "slack.json"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		test.p.Env = &Env{}
+		test.p.WebhookMsg = &slack.WebhookMessage{}
+
+		err := test.p.Validate()
+		if err == nil {
+			t.Errorf("Validate should return err due to %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// redacted replaces secret values in logs and messages.
+const redacted = "[REDACTED]"
+
+// redactHook is a logrus hook removing secret values from log entries.
+type redactHook struct {
+	replacer *strings.Replacer
+}
+
+// newRedactor creates a replacer for removing the secret values,
+// including their JSON escaped form, from the text.
+func newRedactor(secrets ...string) *strings.Replacer {
+	values := make([]string, 0, len(secrets))
+
+	for _, secret := range secrets {
+		if len(strings.TrimSpace(secret)) == 0 {
+			continue
+		}
+
+		values = append(values, secret)
+
+		if escaped := escapeJSON(secret); escaped != secret {
+			values = append(values, escaped)
+		}
+	}
+
+	// replace the longest values first so secrets
+	// containing other secrets are fully removed
+	sort.SliceStable(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+
+	pairs := make([]string, 0, len(values)*2)
+
+	for _, value := range values {
+		pairs = append(pairs, value, redacted)
+	}
+
+	return strings.NewReplacer(pairs...)
+}
+
+// Levels returns the log levels the hook fires for.
+func (h *redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire removes the secret values from the message and fields of the entry.
+func (h *redactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = h.replacer.Replace(entry.Message)
+
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = h.replacer.Replace(v)
+		case error:
+			entry.Data[key] = h.replacer.Replace(v.Error())
+		}
+	}
+
+	return nil
+}
+
+// redact removes the secret values from the text.
+func (p *Plugin) redact(text string) string {
+	if p.Redactor == nil {
+		return text
+	}
+
+	return p.Redactor.Replace(text)
+}
+
+// redactMessage removes the secret values from the message before posting.
+func (p *Plugin) redactMessage(msg *slack.WebhookMessage) error {
+	if p.Redactor == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("unable to marshal webhook message: %w", err)
+	}
+
+	redactedMsg := new(slack.WebhookMessage)
+
+	err = json.Unmarshal([]byte(p.redact(string(b))), redactedMsg)
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal webhook message: %w", err)
+	}
+
+	*msg = *redactedMsg
+
+	return nil
+}
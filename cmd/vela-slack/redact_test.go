@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_newRedactor(t *testing.T) {
+	// setup types
+	r := newRedactor("", "ghp_secret", "https://hooks.slack.com/T0/B0/abc", `pa"ss`)
+
+	// setup tests
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "nothing to hide", want: "nothing to hide"},
+		{input: "token ghp_secret used", want: "token [REDACTED] used"},
+		{input: "posting to https://hooks.slack.com/T0/B0/abc", want: "posting to [REDACTED]"},
+		{input: `{"text": "pa\"ss"}`, want: `{"text": "[REDACTED]"}`},
+		{input: `pa"ss`, want: "[REDACTED]"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := r.Replace(test.input)
+
+		if got != test.want {
+			t.Errorf("Replace is %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestSlack_redactHook_Fire(t *testing.T) {
+	// setup types
+	h := &redactHook{replacer: newRedactor("ghp_secret")}
+
+	entry := &logrus.Entry{
+		Message: "using token ghp_secret",
+		Data: logrus.Fields{
+			"token": "ghp_secret",
+			"error": errors.New("bad credentials ghp_secret"),
+			"count": 1,
+		},
+	}
+
+	err := h.Fire(entry)
+	if err != nil {
+		t.Errorf("Fire returned err: %v", err)
+	}
+
+	if entry.Message != "using token [REDACTED]" {
+		t.Errorf("Fire message is %s", entry.Message)
+	}
+
+	if entry.Data["token"] != redacted {
+		t.Errorf("Fire token field is %v", entry.Data["token"])
+	}
+
+	if entry.Data["error"] != "bad credentials [REDACTED]" {
+		t.Errorf("Fire error field is %v", entry.Data["error"])
+	}
+
+	if entry.Data["count"] != 1 {
+		t.Errorf("Fire count field is %v", entry.Data["count"])
+	}
+}
+
+func TestSlack_Plugin_Exec_Redact(t *testing.T) {
+	// setup types
+	var posted slack.WebhookMessage
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&posted)
+		if err != nil {
+			t.Errorf("Decode error: %v", err)
+		}
+	}))
+	defer ts.Close()
+
+	p := &Plugin{
+		Webhook:  ts.URL,
+		Token:    "ghp_secret",
+		Redactor: newRedactor("ghp_secret", ts.URL),
+		Env: &Env{
+			BuildMessage: "rotate ghp_secret and " + ts.URL,
+		},
+		WebhookMsg: &slack.WebhookMessage{
+			Text: "{{ .BuildMessage }}",
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	want := "rotate [REDACTED] and [REDACTED]"
+
+	if posted.Text != want {
+		t.Errorf("Exec posted text %s, want %s", posted.Text, want)
+	}
+}
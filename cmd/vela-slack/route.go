@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// options for matching routes.
+const (
+	routeMatchFirst = "first"
+	routeMatchAll   = "all"
+)
+
+// Route represents a rule sending messages for matching builds to a channel.
+// Empty fields match any build and the fields may be patterns, e.g. `release/*`.
+type Route struct {
+	Branch  string `json:"branch"`
+	Event   string `json:"event"`
+	Status  string `json:"status"`
+	Channel string `json:"channel"`
+}
+
+// parseRoutes parses the routes from a JSON list.
+func parseRoutes(value string) ([]Route, error) {
+	if len(strings.TrimSpace(value)) == 0 {
+		return nil, nil
+	}
+
+	var routes []Route
+
+	err := json.Unmarshal([]byte(value), &routes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse routes: %w", err)
+	}
+
+	return routes, nil
+}
+
+// validateRoutes validates the routes and that their channels are allowed.
+func (p *Plugin) validateRoutes() error {
+	if len(p.Routes) == 0 {
+		return nil
+	}
+
+	switch p.RouteMatch {
+	case "", routeMatchFirst, routeMatchAll:
+	default:
+		return fmt.Errorf("invalid route_match option provided: %s", p.RouteMatch)
+	}
+
+	// validate that routes aren't combined with direct or ephemeral messages
+	if p.DMAuthor || len(p.EphemeralUser) > 0 {
+		return fmt.Errorf("unable to route direct or ephemeral messages")
+	}
+
+	channels := splitList(p.AllowedChannels)
+
+	for i, route := range p.Routes {
+		if len(route.Channel) == 0 {
+			return fmt.Errorf("no channel provided for route %d", i+1)
+		}
+
+		if len(channels) > 0 && !channelAllowed(route.Channel, channels) {
+			return fmt.Errorf("route channel not allowed: %s", route.Channel)
+		}
+	}
+
+	return nil
+}
+
+// matches returns whether the build matches the route.
+func (r *Route) matches(e *Env) bool {
+	return matchField(r.Branch, e.BuildBranch) &&
+		matchField(r.Event, e.BuildEvent) &&
+		matchField(r.Status, e.BuildStatus)
+}
+
+// matchField returns whether the value matches the pattern.
+// An empty pattern matches any value.
+func matchField(pattern, value string) bool {
+	return len(pattern) == 0 || matchName(value, []string{pattern})
+}
+
+// routeChannels returns the channels of the routes matching the build,
+// only the first one unless all routes are matched. The default channel
+// is used when no route matches, and no channels are returned without
+// a default channel.
+func (p *Plugin) routeChannels(defaultChannel string) []string {
+	var channels []string
+
+	for i := range p.Routes {
+		if !p.Routes[i].matches(p.Env) {
+			continue
+		}
+
+		// send the message once to channels of several routes
+		if !slices.Contains(channels, p.Routes[i].Channel) {
+			channels = append(channels, p.Routes[i].Channel)
+		}
+
+		if p.RouteMatch != routeMatchAll {
+			break
+		}
+	}
+
+	if len(channels) > 0 {
+		return channels
+	}
+
+	if len(p.Routes) > 0 && len(defaultChannel) == 0 {
+		return nil
+	}
+
+	return []string{defaultChannel}
+}
+
+// hasChannel returns whether a channel is provided for the message
+// with the channel, the routes or a direct message.
+func (p *Plugin) hasChannel() bool {
+	return len(p.WebhookMsg.Channel) > 0 || len(p.Routes) > 0 || p.DMAuthor
+}
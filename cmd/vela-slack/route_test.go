@@ -0,0 +1,280 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_parseRoutes(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		value   string
+		want    []Route
+		failure bool
+	}{
+		{value: "", want: nil},
+		{
+			value: `[{"branch": "release/*", "event": "tag", "channel": "#releases"}, {"status": "failure", "channel": "#oncall"}]`,
+			want: []Route{
+				{Branch: "release/*", Event: "tag", Channel: "#releases"},
+				{Status: "failure", Channel: "#oncall"},
+			},
+		},
+		{value: `{"channel": "#oncall"}`, failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := parseRoutes(test.value)
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("parseRoutes should have returned err for %s", test.value)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("parseRoutes returned err for %s: %v", test.value, err)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parseRoutes for %s is %+v, want %+v", test.value, got, test.want)
+		}
+	}
+}
+
+func TestSlack_Plugin_routeChannels(t *testing.T) {
+	// setup types
+	routes := []Route{
+		{Branch: "release/*", Event: "tag", Channel: "#releases"},
+		{Status: "failure", Channel: "#oncall"},
+		{Branch: "main", Channel: "#oncall"},
+	}
+
+	// setup tests
+	tests := []struct {
+		env      Env
+		match    string
+		fallback string
+		want     []string
+	}{
+		{
+			env:   Env{BuildBranch: "release/1.0", BuildEvent: "tag", BuildStatus: "failure"},
+			match: routeMatchFirst,
+			want:  []string{"#releases"},
+		},
+		{
+			env:   Env{BuildBranch: "release/1.0", BuildEvent: "tag", BuildStatus: "failure"},
+			match: routeMatchAll,
+			want:  []string{"#releases", "#oncall"},
+		},
+		{
+			env:   Env{BuildBranch: "main", BuildEvent: "push", BuildStatus: "failure"},
+			match: routeMatchAll,
+			want:  []string{"#oncall"},
+		},
+		{
+			env:      Env{BuildBranch: "feature", BuildEvent: "push", BuildStatus: "success"},
+			match:    routeMatchFirst,
+			fallback: "#builds",
+			want:     []string{"#builds"},
+		},
+		{
+			env:   Env{BuildBranch: "feature", BuildEvent: "push", BuildStatus: "success"},
+			match: routeMatchFirst,
+			want:  nil,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		p := &Plugin{
+			Env:        &test.env,
+			Routes:     routes,
+			RouteMatch: test.match,
+		}
+
+		got := p.routeChannels(test.fallback)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("routeChannels for %+v is %v, want %v", test.env, got, test.want)
+		}
+	}
+}
+
+func TestSlack_Plugin_Validate_Routes(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		routes   []Route
+		match    string
+		dmAuthor bool
+		failure  bool
+	}{
+		{name: "routes without channel", routes: []Route{{Status: "failure", Channel: "#oncall"}}},
+		{name: "invalid match", routes: []Route{{Channel: "#oncall"}}, match: "some", failure: true},
+		{name: "missing channel", routes: []Route{{Status: "failure"}}, failure: true},
+		{name: "channel not allowed", routes: []Route{{Channel: "#random"}}, failure: true},
+		{name: "direct message", routes: []Route{{Channel: "#oncall"}}, dmAuthor: true, failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		p := &Plugin{
+			BotToken:        "xoxb-token",
+			Routes:          test.routes,
+			RouteMatch:      test.match,
+			DMAuthor:        test.dmAuthor,
+			AllowedChannels: []string{"#oncall", "#releases"},
+			WebhookMsg: &slack.WebhookMessage{
+				Text: "hello",
+			},
+		}
+
+		err := p.Validate()
+
+		if test.failure && err == nil {
+			t.Errorf("Validate should have returned err for %s", test.name)
+		}
+
+		if !test.failure && err != nil {
+			t.Errorf("Validate returned err for %s: %v", test.name, err)
+		}
+	}
+}
+
+func TestSlack_Plugin_Exec_Routes(t *testing.T) {
+	// setup types
+	var channels []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			t.Errorf("ParseForm error: %v", err)
+		}
+
+		channel := r.PostForm.Get("channel")
+		channels = append(channels, channel)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"ok": true, "channel": %q, "ts": "1503435956.000247"}`, channel)
+	}))
+	defer ts.Close()
+
+	workspace := t.TempDir()
+
+	p := &Plugin{
+		Env: &Env{
+			BuildBranch:    "release/1.0",
+			BuildEvent:     "tag",
+			BuildStatus:    "failure",
+			BuildWorkspace: workspace,
+		},
+		BotToken:   "xoxb-token",
+		APIURL:     ts.URL + "/",
+		ResultFile: "result.json",
+		RouteMatch: routeMatchAll,
+		Routes: []Route{
+			{Branch: "release/*", Event: "tag", Channel: "#releases"},
+			{Status: "failure", Channel: "#oncall"},
+		},
+		WebhookMsg: &slack.WebhookMessage{
+			Channel: "#builds",
+			Text:    "hello",
+		},
+	}
+
+	// run test
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	want := []string{"#releases", "#oncall"}
+
+	if !reflect.DeepEqual(channels, want) {
+		t.Errorf("Exec posted to %v, want %v", channels, want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(workspace, "result.json"))
+	if err != nil {
+		t.Errorf("ReadFile returned err: %v", err)
+	}
+
+	var got result
+
+	err = json.Unmarshal(data, &got)
+	if err != nil {
+		t.Errorf("Unmarshal returned err: %v", err)
+	}
+
+	if len(got.Targets) != 2 || got.Targets[0].Channel != "#releases" || got.Targets[1].Channel != "#oncall" {
+		t.Errorf("result targets are %+v, want #releases and #oncall", got.Targets)
+	}
+}
+
+func TestSlack_Plugin_Exec_Routes_Single_Match(t *testing.T) {
+	// setup types
+	var channels []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			t.Errorf("ParseForm error: %v", err)
+		}
+
+		channel := r.PostForm.Get("channel")
+		channels = append(channels, channel)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"ok": true, "channel": %q, "ts": "1503435956.000247"}`, channel)
+	}))
+	defer ts.Close()
+
+	// setup tests
+	tests := []string{routeMatchFirst, routeMatchAll}
+
+	// run tests
+	for _, match := range tests {
+		channels = nil
+
+		p := &Plugin{
+			Env: &Env{
+				BuildBranch: "main",
+				BuildEvent:  "push",
+				BuildStatus: "failure",
+			},
+			BotToken:   "xoxb-token",
+			APIURL:     ts.URL + "/",
+			RouteMatch: match,
+			Routes: []Route{
+				{Status: "failure", Channel: "#oncall"},
+			},
+			WebhookMsg: &slack.WebhookMessage{
+				Channel: "#builds",
+				Text:    "hello",
+			},
+		}
+
+		err := p.Exec()
+		if err != nil {
+			t.Errorf("Exec returned err for %s: %v", match, err)
+		}
+
+		if !reflect.DeepEqual(channels, []string{"#oncall"}) {
+			t.Errorf("Exec for %s posted to %v, want [#oncall]", match, channels)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// schedule schedules the message to be posted by Slack at the post_at time.
+func (p *Plugin) schedule(ctx context.Context, msg *slack.WebhookMessage) (*messageRef, error) {
+	value, err := renderTemplate("post_at", p.PostAt, p.Env)
+	if err != nil {
+		return nil, classify(classTemplate, err)
+	}
+
+	postAt, err := parsePostAt(value, time.Now())
+	if err != nil {
+		return nil, classify(classConfig, err)
+	}
+
+	enforceLimits(msg, p.Env.BuildLink)
+
+	logrus.Infof("Scheduling message for %s...", postAt.Format(time.RFC3339))
+
+	channel, id, err := p.client().ScheduleMessageContext(
+		ctx, msg.Channel, strconv.FormatInt(postAt.Unix(), 10), msgOptions(msg)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to schedule message: %w", err)
+	}
+
+	logrus.Infof("Scheduled message %s in channel %s", id, channel)
+
+	return &messageRef{Channel: channel, ScheduledMessageID: id}, nil
+}
+
+// cancelSchedule deletes a message scheduled by a previous step.
+func (p *Plugin) cancelSchedule(ctx context.Context) error {
+	ref := &messageRef{
+		Channel:            p.WebhookMsg.Channel,
+		ScheduledMessageID: p.ScheduledMessageID,
+	}
+
+	// use the message stored by a previous step
+	if len(ref.ScheduledMessageID) == 0 {
+		stored, err := readMessageRef(workspacePath(p.Env.BuildWorkspace, p.TSFile))
+		if err != nil {
+			return err
+		}
+
+		ref = stored
+	}
+
+	logrus.Infof("Canceling scheduled message %s...", ref.ScheduledMessageID)
+
+	_, err := p.client().DeleteScheduledMessageContext(ctx, &slack.DeleteScheduledMessageParameters{
+		Channel:            ref.Channel,
+		ScheduledMessageID: ref.ScheduledMessageID,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to cancel scheduled message %s: %w", ref.ScheduledMessageID, err)
+	}
+
+	return nil
+}
+
+// parsePostAt parses the post_at value as a duration relative to now,
+// an RFC 3339 timestamp or a unix timestamp.
+func parsePostAt(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	d, err := time.ParseDuration(value)
+	if err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("post_at duration must be positive: %s", value)
+		}
+
+		return now.Add(d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, checkPostAt(t, now)
+	}
+
+	unix, err := strconv.ParseInt(value, 10, 64)
+	if err == nil {
+		t = time.Unix(unix, 0)
+
+		return t, checkPostAt(t, now)
+	}
+
+	return time.Time{}, fmt.Errorf("invalid post_at provided: %s", value)
+}
+
+// checkPostAt validates the time is in the future.
+func checkPostAt(t, now time.Time) error {
+	if !t.After(now) {
+		return fmt.Errorf("post_at must be in the future: %s", t.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// validateCancel validates the configuration for the cancel mode.
+func (p *Plugin) validateCancel() error {
+	// validate that a bot token was supplied
+	if !p.usesAPI() {
+		return fmt.Errorf("no bot token provided for canceling scheduled messages")
+	}
+
+	// validate that a scheduled message was supplied
+	if len(p.ScheduledMessageID) == 0 && len(p.TSFile) == 0 {
+		return fmt.Errorf("no scheduled_message_id or ts_file provided for canceling")
+	}
+
+	// validate that a channel was supplied with the scheduled message
+	if len(p.ScheduledMessageID) > 0 && len(p.WebhookMsg.Channel) == 0 {
+		return fmt.Errorf("no channel provided for canceling")
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_parsePostAt(t *testing.T) {
+	// setup types
+	now := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+
+	// setup tests
+	tests := []struct {
+		value   string
+		want    time.Time
+		failure bool
+	}{
+		{value: "30m", want: now.Add(30 * time.Minute)},
+		{value: " 1h ", want: now.Add(time.Hour)},
+		{value: "2024-05-01T13:00:00Z", want: now.Add(time.Hour)},
+		{value: strconv.FormatInt(now.Add(time.Hour).Unix(), 10), want: now.Add(time.Hour)},
+		{value: "-5m", failure: true},
+		{value: "2024-05-01T11:00:00Z", failure: true},
+		{value: "tomorrow", failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := parsePostAt(test.value, now)
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("parsePostAt should have returned err for %s", test.value)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("parsePostAt returned err: %v", err)
+		}
+
+		if !got.Equal(test.want) {
+			t.Errorf("parsePostAt is %v, want %v", got, test.want)
+		}
+	}
+}
+
+func TestSlack_Plugin_Exec_Schedule_And_Cancel(t *testing.T) {
+	// setup types
+	var postAt, deleted string
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+
+	defer ts.Close()
+
+	mux.HandleFunc("/chat.scheduleMessage", func(w http.ResponseWriter, r *http.Request) {
+		postAt = r.FormValue("post_at")
+
+		fmt.Fprintln(w, `{"ok": true, "channel": "C024BE91L", "scheduled_message_id": "Q1298393284"}`)
+	})
+	mux.HandleFunc("/chat.deleteScheduledMessage", func(w http.ResponseWriter, r *http.Request) {
+		deleted = r.FormValue("channel") + " " + r.FormValue("scheduled_message_id")
+
+		fmt.Fprintln(w, `{"ok": true}`)
+	})
+
+	file := filepath.Join(t.TempDir(), "slack.json")
+
+	p := &Plugin{
+		Env: &Env{
+			RepositoryTimeout: 30,
+		},
+		BotToken: "xoxb-token",
+		APIURL:   ts.URL + "/",
+		TSFile:   file,
+		PostAt:   "{{ .RepositoryTimeout }}m",
+		WebhookMsg: &slack.WebhookMessage{
+			Channel: "#deploys",
+			Text:    "deploy window closes soon",
+		},
+	}
+
+	start := time.Now()
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	unix, err := strconv.ParseInt(postAt, 10, 64)
+	if err != nil {
+		t.Fatalf("Exec scheduled message with post_at %q", postAt)
+	}
+
+	if unix < start.Add(30*time.Minute).Unix() || unix > time.Now().Add(30*time.Minute).Unix() {
+		t.Errorf("Exec scheduled message for %d", unix)
+	}
+
+	p.Mode = modeCancel
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	if deleted != "C024BE91L Q1298393284" {
+		t.Errorf("Exec canceled scheduled message %q", deleted)
+	}
+}
+
+func TestSlack_Plugin_Validate_Schedule(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		p    *Plugin
+	}{
+		{
+			name: "missing bot token for post_at",
+			p:    &Plugin{Webhook: "webhook_url", PostAt: "30m"},
+		},
+		{
+			name: "files with post_at",
+			p:    &Plugin{BotToken: "xoxb-token", PostAt: "30m", Files: []string{"*.xml"}},
+		},
+		{
+			name: "missing bot token for cancel",
+			p:    &Plugin{Webhook: "webhook_url", Mode: modeCancel, TSFile: "slack.json"},
+		},
+		{
+			name: "missing scheduled message",
+			p:    &Plugin{BotToken: "xoxb-token", Mode: modeCancel},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		test.p.Env = &Env{}
+		test.p.WebhookMsg = &slack.WebhookMessage{Channel: "#deploys", Text: "hello"}
+
+		err := test.p.Validate()
+		if err == nil {
+			t.Errorf("Validate should return err due to %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// Adaptive Card schema details for Microsoft Teams.
+const (
+	adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+	adaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	adaptiveCardVersion     = "1.4"
+)
+
+type (
+	// teams delivers messages with a Microsoft Teams webhook.
+	teams struct {
+		url    string
+		client *http.Client
+	}
+
+	// teamsMessage represents the payload for a Microsoft Teams webhook.
+	//
+	// https://learn.microsoft.com/en-us/microsoftteams/platform/webhooks-and-connectors/how-to/connectors-using
+	teamsMessage struct {
+		Type        string            `json:"type"`
+		Attachments []teamsAttachment `json:"attachments"`
+	}
+
+	// teamsAttachment represents the Adaptive Card attachment of the message.
+	teamsAttachment struct {
+		ContentType string       `json:"contentType"`
+		Content     adaptiveCard `json:"content"`
+	}
+
+	// adaptiveCard represents an Adaptive Card.
+	//
+	// https://adaptivecards.io/explorer/AdaptiveCard.html
+	adaptiveCard struct {
+		Schema  string            `json:"$schema"`
+		Type    string            `json:"type"`
+		Version string            `json:"version"`
+		Body    []adaptiveElement `json:"body"`
+		Actions []adaptiveAction  `json:"actions,omitempty"`
+	}
+
+	// adaptiveElement represents an element in the body of an Adaptive Card.
+	adaptiveElement struct {
+		Type     string            `json:"type"`
+		Text     string            `json:"text,omitempty"`
+		Wrap     bool              `json:"wrap,omitempty"`
+		Weight   string            `json:"weight,omitempty"`
+		Size     string            `json:"size,omitempty"`
+		IsSubtle bool              `json:"isSubtle,omitempty"`
+		URL      string            `json:"url,omitempty"`
+		Style    string            `json:"style,omitempty"`
+		Facts    []adaptiveFact    `json:"facts,omitempty"`
+		Items    []adaptiveElement `json:"items,omitempty"`
+	}
+
+	// adaptiveFact represents a fact in an Adaptive Card fact set.
+	adaptiveFact struct {
+		Title string `json:"title"`
+		Value string `json:"value"`
+	}
+
+	// adaptiveAction represents an action of an Adaptive Card.
+	adaptiveAction struct {
+		Type  string `json:"type"`
+		Title string `json:"title"`
+		URL   string `json:"url"`
+	}
+)
+
+// Notify posts the message to the Microsoft Teams webhook.
+func (n *teams) Notify(ctx context.Context, msg *slack.WebhookMessage) (string, string, error) {
+	logrus.Info("Posting Microsoft Teams webhook message...")
+
+	err := postJSON(ctx, n.client, n.url, toTeams(msg))
+	if err != nil {
+		return "", "", fmt.Errorf("unable to post Microsoft Teams webhook message: %w", err)
+	}
+
+	return "", "", nil
+}
+
+// toTeams converts the Slack message into a Microsoft Teams message
+// with an Adaptive Card containing a container for each attachment.
+func toTeams(msg *slack.WebhookMessage) *teamsMessage {
+	card := adaptiveCard{
+		Schema:  adaptiveCardSchema,
+		Type:    "AdaptiveCard",
+		Version: adaptiveCardVersion,
+	}
+
+	if len(msg.Text) > 0 {
+		card.Body = append(card.Body, adaptiveElement{Type: "TextBlock", Text: markdownLinks(msg.Text), Wrap: true})
+	}
+
+	for _, a := range msg.Attachments {
+		var items []adaptiveElement
+
+		if len(a.Pretext) > 0 {
+			items = append(items, adaptiveElement{Type: "TextBlock", Text: markdownLinks(a.Pretext), Wrap: true})
+		}
+
+		if len(a.AuthorName) > 0 {
+			items = append(items, adaptiveElement{Type: "TextBlock", Text: a.AuthorName, IsSubtle: true, Wrap: true})
+		}
+
+		if len(a.Title) > 0 {
+			items = append(items, adaptiveElement{Type: "TextBlock", Text: a.Title, Weight: "Bolder", Size: "Medium", Wrap: true})
+		}
+
+		if len(a.Text) > 0 {
+			items = append(items, adaptiveElement{Type: "TextBlock", Text: markdownLinks(a.Text), Wrap: true})
+		}
+
+		if len(a.Fields) > 0 {
+			facts := make([]adaptiveFact, 0, len(a.Fields))
+
+			for _, f := range a.Fields {
+				facts = append(facts, adaptiveFact{Title: f.Title, Value: markdownLinks(f.Value)})
+			}
+
+			items = append(items, adaptiveElement{Type: "FactSet", Facts: facts})
+		}
+
+		if len(a.ImageURL) > 0 {
+			items = append(items, adaptiveElement{Type: "Image", URL: a.ImageURL})
+		}
+
+		if len(a.Footer) > 0 {
+			items = append(items, adaptiveElement{Type: "TextBlock", Text: markdownLinks(a.Footer), Size: "Small", IsSubtle: true, Wrap: true})
+		}
+
+		if len(a.TitleLink) > 0 {
+			title := a.Title
+			if len(title) == 0 {
+				title = "Open"
+			}
+
+			card.Actions = append(card.Actions, adaptiveAction{Type: "Action.OpenUrl", Title: title, URL: a.TitleLink})
+		}
+
+		card.Body = append(card.Body, adaptiveElement{Type: "Container", Style: containerStyle(a.Color), Items: items})
+	}
+
+	return &teamsMessage{
+		Type:        "message",
+		Attachments: []teamsAttachment{{ContentType: adaptiveCardContentType, Content: card}},
+	}
+}
+
+// containerStyle converts the attachment color into an Adaptive Card container style.
+func containerStyle(color string) string {
+	switch hexColor(color) {
+	case "":
+		return ""
+	case colorGood:
+		return "good"
+	case colorWarning:
+		return "warning"
+	case colorDanger:
+		return "attention"
+	default:
+		return "emphasis"
+	}
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_toTeams(t *testing.T) {
+	// setup types
+	msg := &slack.WebhookMessage{
+		Text: "build <https://vela.example.com/1|#1> finished",
+		Attachments: []slack.Attachment{
+			{
+				Color:     "danger",
+				Title:     "octocat/hello-world",
+				TitleLink: "https://github.com/octocat/hello-world",
+				Fields:    []slack.AttachmentField{{Title: "Priority", Value: "High"}},
+			},
+		},
+	}
+
+	want := `{"type":"message","attachments":[{"contentType":"application/vnd.microsoft.card.adaptive","content":{` +
+		`"$schema":"http://adaptivecards.io/schemas/adaptive-card.json","type":"AdaptiveCard","version":"1.4","body":[` +
+		`{"type":"TextBlock","text":"build [#1](https://vela.example.com/1) finished","wrap":true},` +
+		`{"type":"Container","style":"attention","items":[` +
+		`{"type":"TextBlock","text":"octocat/hello-world","wrap":true,"weight":"Bolder","size":"Medium"},` +
+		`{"type":"FactSet","facts":[{"title":"Priority","value":"High"}]}]}],` +
+		`"actions":[{"type":"Action.OpenUrl","title":"octocat/hello-world","url":"https://github.com/octocat/hello-world"}]}}]}`
+
+	got, err := json.Marshal(toTeams(msg))
+	if err != nil {
+		t.Errorf("Marshal returned err: %v", err)
+	}
+
+	if string(got) != want {
+		t.Errorf("toTeams is %s, want %s", got, want)
+	}
+}
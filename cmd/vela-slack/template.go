@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+// colors used by Slack for the good, warning and danger attachment colors.
+const (
+	colorGood    = "#2eb886"
+	colorWarning = "#daa038"
+	colorDanger  = "#a30200"
+	colorNeutral = "#808080"
+)
+
+// templateFuncs returns the sprig function map extended
+// with functions for building Slack formatted text.
+//
+// The sprig env and expandenv functions are replaced so
+// templates can only read the variables exposed in vars.
+func templateFuncs(vars map[string]string) template.FuncMap {
+	funcs := sprig.TxtFuncMap()
+
+	funcs["env"] = func(name string) string {
+		return vars[name]
+	}
+
+	funcs["expandenv"] = func(s string) string {
+		return os.Expand(s, func(name string) string {
+			return vars[name]
+		})
+	}
+
+	funcs["slackEscape"] = slackEscape
+	funcs["slackLink"] = slackLink
+	funcs["slackDate"] = slackDate
+	funcs["mention"] = mention
+	funcs["userGroup"] = userGroup
+	funcs["truncate"] = truncate
+	funcs["statusColor"] = statusColor
+	funcs["statusEmoji"] = statusEmoji
+
+	return funcs
+}
+
+// slackEscape escapes the control characters used by Slack mrkdwn.
+//
+// https://api.slack.com/reference/surfaces/formatting#escaping
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// slackLink creates a Slack link to the url with the escaped text.
+func slackLink(url, text string) string {
+	if len(text) == 0 {
+		return fmt.Sprintf("<%s>", url)
+	}
+
+	return fmt.Sprintf("<%s|%s>", url, slackEscape(text))
+}
+
+// slackDate creates a Slack date token that renders the unix timestamp
+// in the reader's timezone using the provided format.
+//
+// https://api.slack.com/reference/surfaces/formatting#date-formatting
+func slackDate(ts interface{}, format string) (string, error) {
+	unix, err := toUnix(ts)
+	if err != nil {
+		return "", err
+	}
+
+	fallback := time.Unix(unix, 0).UTC().Format(time.RFC1123)
+
+	return fmt.Sprintf("<!date^%d^%s|%s>", unix, format, fallback), nil
+}
+
+// mention creates a Slack mention for the user id or special
+// mentions like here, channel and everyone.
+func mention(id string) string {
+	id = strings.TrimPrefix(id, "@")
+
+	switch id {
+	case "here", "channel", "everyone":
+		return fmt.Sprintf("<!%s>", id)
+	default:
+		return fmt.Sprintf("<@%s>", id)
+	}
+}
+
+// userGroup creates a Slack mention for the user group id.
+func userGroup(id string) string {
+	return fmt.Sprintf("<!subteam^%s>", id)
+}
+
+// truncate shortens the string to n characters and adds
+// an ellipsis when any characters were removed.
+func truncate(n int, s string) string {
+	r := []rune(s)
+	if n < 0 || len(r) <= n {
+		return s
+	}
+
+	return trimEscape(string(r[:n])) + "…"
+}
+
+// trimEscape removes a partial JSON escape sequence, like a trailing
+// backslash or an incomplete \uXXXX, from the end of the string since
+// the template is rendered inside JSON.
+func trimEscape(s string) string {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			continue
+		}
+
+		size := 2
+		if i+1 < len(s) && s[i+1] == 'u' {
+			size = 6
+		}
+
+		if i+size > len(s) {
+			return s[:i]
+		}
+
+		i += size - 1
+	}
+
+	return s
+}
+
+// statusColor returns the attachment color for the build status.
+func statusColor(status string) string {
+	switch strings.ToLower(status) {
+	case "success":
+		return colorGood
+	case "failure", "error":
+		return colorDanger
+	case "pending", "running":
+		return colorWarning
+	default:
+		return colorNeutral
+	}
+}
+
+// statusEmoji returns the emoji for the build status.
+func statusEmoji(status string) string {
+	switch strings.ToLower(status) {
+	case "success":
+		return ":white_check_mark:"
+	case "failure":
+		return ":x:"
+	case "error":
+		return ":exclamation:"
+	case "killed", "canceled":
+		return ":no_entry_sign:"
+	case "pending":
+		return ":hourglass:"
+	case "running":
+		return ":hourglass_flowing_sand:"
+	default:
+		return ":grey_question:"
+	}
+}
+
+// toUnix converts the template value to a unix timestamp.
+func toUnix(v interface{}) (int64, error) {
+	switch t := v.(type) {
+	case int:
+		return int64(t), nil
+	case int64:
+		return t, nil
+	case time.Time:
+		return t.Unix(), nil
+	case string:
+		i, err := strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid timestamp %q: %w", t, err)
+		}
+
+		return i, nil
+	default:
+		return 0, fmt.Errorf("invalid timestamp type %T", v)
+	}
+}
+
+// renderTemplate executes the text as a template against the environment.
+func renderTemplate(name, text string, env *Env) (string, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs(env.Vars)).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse %s template: %w", name, err)
+	}
+
+	buffer := new(strings.Builder)
+
+	err = tmpl.Execute(buffer, env)
+	if err != nil {
+		return "", fmt.Errorf("unable to execute %s template: %w", name, err)
+	}
+
+	return buffer.String(), nil
+}
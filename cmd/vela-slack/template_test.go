@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+)
+
+func TestSlack_slackEscape(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "plain", want: "plain"},
+		{input: "fix <nil> & more", want: "fix &lt;nil&gt; &amp; more"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := slackEscape(test.input)
+
+		if got != test.want {
+			t.Errorf("slackEscape is %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestSlack_slackLink(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		url  string
+		text string
+		want string
+	}{
+		{url: "https://vela.example.com", text: "", want: "<https://vela.example.com>"},
+		{url: "https://vela.example.com", text: "Build <1>", want: "<https://vela.example.com|Build &lt;1&gt;>"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := slackLink(test.url, test.text)
+
+		if got != test.want {
+			t.Errorf("slackLink is %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestSlack_slackDate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		ts      interface{}
+		want    string
+		failure bool
+	}{
+		{ts: 1563474076, want: "<!date^1563474076^{date_short} {time}|Thu, 18 Jul 2019 18:21:16 UTC>"},
+		{ts: "1563474076", want: "<!date^1563474076^{date_short} {time}|Thu, 18 Jul 2019 18:21:16 UTC>"},
+		{ts: "yesterday", failure: true},
+		{ts: 1.5, failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := slackDate(test.ts, "{date_short} {time}")
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("slackDate should have returned err for %v", test.ts)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("slackDate returned err: %v", err)
+		}
+
+		if got != test.want {
+			t.Errorf("slackDate is %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestSlack_mention(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{id: "U024BE7LH", want: "<@U024BE7LH>"},
+		{id: "@U024BE7LH", want: "<@U024BE7LH>"},
+		{id: "here", want: "<!here>"},
+		{id: "channel", want: "<!channel>"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := mention(test.id)
+
+		if got != test.want {
+			t.Errorf("mention is %s, want %s", got, test.want)
+		}
+	}
+
+	if got := userGroup("SAZ94GDB8"); got != "<!subteam^SAZ94GDB8>" {
+		t.Errorf("userGroup is %s, want <!subteam^SAZ94GDB8>", got)
+	}
+}
+
+func TestSlack_truncate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		n     int
+		input string
+		want  string
+	}{
+		{n: 10, input: "short", want: "short"},
+		{n: 5, input: "longer message", want: "longe…"},
+		{n: 3, input: "héllo", want: "hél…"},
+		{n: 3, input: `ab\"cd`, want: "ab…"},
+		{n: 4, input: `ab\\cd`, want: `ab\\…`},
+		{n: 3, input: `\u0001\u0001`, want: "…"},
+		{n: 8, input: `\u0001\u0001`, want: `\u0001…`},
+		{n: 3, input: escapeJSON("<<<<"), want: "<<<…"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := truncate(test.n, test.input)
+
+		if got != test.want {
+			t.Errorf("truncate is %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestSlack_statusColor_statusEmoji(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		status string
+		color  string
+		emoji  string
+	}{
+		{status: "success", color: colorGood, emoji: ":white_check_mark:"},
+		{status: "failure", color: colorDanger, emoji: ":x:"},
+		{status: "running", color: colorWarning, emoji: ":hourglass_flowing_sand:"},
+		{status: "canceled", color: colorNeutral, emoji: ":no_entry_sign:"},
+		{status: "", color: colorNeutral, emoji: ":grey_question:"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		if got := statusColor(test.status); got != test.color {
+			t.Errorf("statusColor for %s is %s, want %s", test.status, got, test.color)
+		}
+
+		if got := statusEmoji(test.status); got != test.emoji {
+			t.Errorf("statusEmoji for %s is %s, want %s", test.status, got, test.emoji)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+)
+
+// uploadFiles uploads the files matching the provided patterns and shares
+// them to the channel. The files are shared into the thread when provided.
+func (p *Plugin) uploadFiles(ctx context.Context, channel, thread string) error {
+	files, err := matchFiles(p.Env.BuildWorkspace, p.Files)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("unable to stat file %s: %w", file, err)
+		}
+
+		// Slack rejects uploads without any content
+		if info.Size() == 0 {
+			logrus.Warnf("Skipping empty file %s", file)
+
+			continue
+		}
+
+		logrus.Infof("Uploading file %s...", file)
+
+		_, err = p.client().UploadFileV2Context(ctx, slack.UploadFileV2Parameters{
+			File:            file,
+			FileSize:        int(info.Size()),
+			Filename:        filepath.Base(file),
+			Channel:         channel,
+			ThreadTimestamp: thread,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to upload file %s: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+// matchFiles returns the files matching the glob patterns in the
+// workspace. Patterns and files outside the workspace are rejected.
+func matchFiles(workspace string, patterns []string) ([]string, error) {
+	var files []string
+
+	for _, pattern := range patterns {
+		pattern, err := workspaceFile(workspace, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file pattern: %w", err)
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file pattern %s: %w", pattern, err)
+		}
+
+		if len(matches) == 0 {
+			logrus.Warnf("No files found matching %s", pattern)
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, fmt.Errorf("unable to stat file %s: %w", match, err)
+			}
+
+			if info.IsDir() {
+				continue
+			}
+
+			// symbolic links may point outside the workspace
+			err = checkWorkspaceFile(workspace, match)
+			if err != nil {
+				return nil, err
+			}
+
+			files = append(files, match)
+		}
+	}
+
+	return files, nil
+}
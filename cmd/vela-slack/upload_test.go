@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_matchFiles(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	for _, name := range []string{"report.xml", "coverage.html", "notes.txt"} {
+		err := os.WriteFile(filepath.Join(dir, name), []byte("content"), 0o600)
+		if err != nil {
+			t.Fatalf("WriteFile error: %v", err)
+		}
+	}
+
+	err := os.Mkdir(filepath.Join(dir, "dir.xml"), 0o700)
+	if err != nil {
+		t.Fatalf("Mkdir error: %v", err)
+	}
+
+	got, err := matchFiles(dir, []string{"*.xml", "./reports/../*.html", "*.png"})
+	if err != nil {
+		t.Errorf("matchFiles returned err: %v", err)
+	}
+
+	want := []string{filepath.Join(dir, "report.xml"), filepath.Join(dir, "coverage.html")}
+
+	if len(got) != len(want) {
+		t.Fatalf("matchFiles is %v, want %v", got, want)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("matchFiles is %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSlack_matchFiles_Outside_Workspace(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+	workspace := filepath.Join(dir, "workspace")
+	secret := filepath.Join(dir, "secret.txt")
+
+	err := os.Mkdir(workspace, 0o700)
+	if err != nil {
+		t.Fatalf("Mkdir error: %v", err)
+	}
+
+	err = os.WriteFile(secret, []byte("secret"), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+
+	err = os.Symlink(secret, filepath.Join(workspace, "link.txt"))
+	if err != nil {
+		t.Fatalf("Symlink error: %v", err)
+	}
+
+	// setup tests
+	tests := []string{
+		secret,
+		filepath.Join(dir, "*.txt"),
+		"../*.txt",
+		"reports/../../secret.txt",
+		"*.txt",
+	}
+
+	// run tests
+	for _, pattern := range tests {
+		got, err := matchFiles(workspace, []string{pattern})
+		if err == nil {
+			t.Errorf("matchFiles should have returned err for %s, got %v", pattern, got)
+		}
+	}
+}
+
+func TestSlack_Plugin_Exec_Files(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "report.xml"), []byte("<testsuite/>"), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+
+	var complete url.Values
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+
+	defer ts.Close()
+
+	mux.HandleFunc("/chat.postMessage", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, `{"ok": true, "channel": "C024BE91L", "ts": "1503435956.000247"}`)
+	})
+	mux.HandleFunc("/files.getUploadURLExternal", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"ok": true, "upload_url": "%s/upload", "file_id": "F123"}`, ts.URL)
+	})
+	mux.HandleFunc("/upload", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, "OK")
+	})
+	mux.HandleFunc("/files.completeUploadExternal", func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			t.Errorf("ParseForm error: %v", err)
+		}
+
+		complete = r.PostForm
+
+		fmt.Fprintln(w, `{"ok": true, "files": [{"id": "F123", "title": "report.xml"}]}`)
+	})
+
+	p := &Plugin{
+		Env: &Env{
+			BuildWorkspace: dir,
+		},
+		BotToken:    "xoxb-token",
+		APIURL:      ts.URL + "/",
+		Files:       []string{"*.xml"},
+		FilesThread: true,
+		WebhookMsg: &slack.WebhookMessage{
+			Channel: "#builds",
+			Text:    "hello",
+		},
+	}
+
+	err = p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	if complete == nil {
+		t.Fatal("Exec did not complete the file upload")
+	}
+
+	if got := complete.Get("channel_id"); got != "C024BE91L" {
+		t.Errorf("Exec shared file to channel %s, want C024BE91L", got)
+	}
+
+	if got := complete.Get("thread_ts"); got != "1503435956.000247" {
+		t.Errorf("Exec shared file to thread %s, want 1503435956.000247", got)
+	}
+}
+
+func TestSlack_Plugin_Validate_Files_Without_Token(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Webhook: "webhook_url",
+		Env:     &Env{},
+		Files:   []string{"*.xml"},
+		WebhookMsg: &slack.WebhookMessage{
+			Text: "hello",
+		},
+	}
+
+	err := p.Validate()
+	if err == nil {
+		t.Error("Validate should return err due to missing bot token")
+	}
+}
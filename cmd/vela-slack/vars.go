@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"path"
+	"strings"
+)
+
+// velaPrefix is the prefix of the variables injected by Vela.
+const velaPrefix = "VELA_"
+
+// loadVars returns the VELA_* variables and the variables matching the
+// allowed names or patterns from the environment. Variables with names
+// used for credentials are never included. The values are escaped so
+// they can be placed in the JSON message template.
+func loadVars(environ, allowed []string) map[string]string {
+	vars := make(map[string]string)
+
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || isSecretName(name) {
+			continue
+		}
+
+		if !strings.HasPrefix(name, velaPrefix) && !matchName(name, allowed) {
+			continue
+		}
+
+		vars[name] = escapeJSON(value)
+	}
+
+	return vars
+}
+
+// matchName checks if the name matches any of the patterns.
+func matchName(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		match, err := path.Match(pattern, name)
+		if err == nil && match {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSecretName checks if the variable or header name is commonly used for
+// credentials. The words must be whole parts of the name, separated by
+// underscores, dashes or dots, so names like VELA_BUILD_AUTHOR are kept.
+func isSecretName(name string) bool {
+	parts := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '_' || r == '-' || r == '.'
+	})
+
+	for _, part := range parts {
+		switch part {
+		case "auth", "authorization", "token", "key", "apikey", "secret",
+			"signature", "cookie", "password", "passwd", "netrc", "credentials":
+			return true
+		}
+	}
+
+	return false
+}
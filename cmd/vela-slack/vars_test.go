@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_loadVars(t *testing.T) {
+	// setup types
+	environ := []string{
+		"VELA_BUILD_EVENT=deployment",
+		"VELA_BUILD_AUTHOR=octocat",
+		"VELA_BUILD_AUTHOR_EMAIL=octocat@github.com",
+		"VELA_DEPLOYMENT=production",
+		"VELA_NETRC_PASSWORD=ghp_secret",
+		"VELA_BUILD_TOKEN=token",
+		"DEPLOY_TARGET=us-east-1",
+		"DEPLOY_API_KEY=key",
+		"CUSTOM_NOTE=say \"hi\"",
+		"HOME=/root",
+	}
+
+	want := map[string]string{
+		"VELA_BUILD_EVENT":        "deployment",
+		"VELA_BUILD_AUTHOR":       "octocat",
+		"VELA_BUILD_AUTHOR_EMAIL": "octocat@github.com",
+		"VELA_DEPLOYMENT":         "production",
+		"DEPLOY_TARGET":           "us-east-1",
+		"CUSTOM_NOTE":             `say \"hi\"`,
+	}
+
+	got := loadVars(environ, []string{"DEPLOY_*", "CUSTOM_NOTE"})
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadVars is %v, want %v", got, want)
+	}
+}
+
+func TestSlack_isSecretName(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "VELA_NETRC_PASSWORD", want: true},
+		{name: "GITHUB_TOKEN", want: true},
+		{name: "DEPLOY_API_KEY", want: true},
+		{name: "Authorization", want: true},
+		{name: "X-Auth-Token", want: true},
+		{name: "X-Hub-Signature", want: true},
+		{name: "VELA_BUILD_AUTHOR", want: false},
+		{name: "VELA_BUILD_AUTHOR_EMAIL", want: false},
+		{name: "VELA_REPO_KEYWORDS", want: false},
+		{name: "Content-Type", want: false},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := isSecretName(test.name)
+
+		if got != test.want {
+			t.Errorf("isSecretName for %s is %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSlack_Plugin_Exec_Vars(t *testing.T) {
+	// setup types
+	var posted slack.WebhookMessage
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&posted)
+		if err != nil {
+			t.Errorf("Decode error: %v", err)
+		}
+	}))
+	defer ts.Close()
+
+	t.Setenv("SLACK_TEST_SECRET", "hidden")
+
+	p := &Plugin{
+		Webhook: ts.URL,
+		Env: &Env{
+			Vars: map[string]string{
+				"VELA_DEPLOYMENT": "production",
+				"DEPLOY_TARGET":   "us-east-1",
+			},
+		},
+		WebhookMsg: &slack.WebhookMessage{
+			Text: "{{ .Vars.VELA_DEPLOYMENT }} {{ env \"DEPLOY_TARGET\" }} {{ expandenv \"$DEPLOY_TARGET\" }} [{{ env \"SLACK_TEST_SECRET\" }}]",
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	want := "production us-east-1 us-east-1 []"
+
+	if posted.Text != want {
+		t.Errorf("Exec posted text %s, want %s", posted.Text, want)
+	}
+}
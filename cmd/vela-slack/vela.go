@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	api "github.com/go-vela/server/api/types"
+)
+
+// maxSteps is the most steps requested from the Vela API.
+const maxSteps = 100
+
+// Step represents a step of the build from the Vela API.
+type Step struct {
+	Number   int
+	Name     string
+	Stage    string
+	Status   string
+	Duration string
+	Error    string
+}
+
+// loadSteps adds the steps of the build from the Vela API to the
+// environment. Problems reading the steps are logged so the message
+// is still sent.
+func (p *Plugin) loadSteps() {
+	if !p.VelaAPI {
+		return
+	}
+
+	steps, err := p.steps(context.Background())
+	if err != nil {
+		logrus.Warnf("unable to read steps from Vela API: %v", err)
+
+		return
+	}
+
+	p.Env.Steps = steps
+}
+
+// steps returns the steps of the build from the Vela API.
+func (p *Plugin) steps(ctx context.Context) ([]Step, error) {
+	path := fmt.Sprintf(
+		"/api/v1/repos/%s/%s/builds/%d/steps",
+		url.PathEscape(p.Env.RepositoryOrg), url.PathEscape(p.Env.RepositoryName), p.Env.BuildNumber,
+	)
+
+	var velaSteps []api.Step
+
+	err := p.velaGet(ctx, path, url.Values{"per_page": {fmt.Sprint(maxSteps)}}, &velaSteps)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]Step, 0, len(velaSteps))
+
+	for _, s := range velaSteps {
+		// the template is rendered inside JSON so the text must be escaped
+		steps = append(steps, Step{
+			Number:   s.GetNumber(),
+			Name:     escapeJSON(s.GetName()),
+			Stage:    escapeJSON(s.GetStage()),
+			Status:   s.GetStatus(),
+			Duration: stepDuration(s.GetStarted(), s.GetFinished()),
+			Error:    escapeJSON(s.GetError()),
+		})
+	}
+
+	return steps, nil
+}
+
+// velaGet sends a GET request to the Vela API and decodes the JSON response.
+func (p *Plugin) velaGet(ctx context.Context, path string, query url.Values, v interface{}) error {
+	if len(p.VelaAddr) == 0 {
+		return fmt.Errorf("no Vela address provided")
+	}
+
+	u := strings.TrimSuffix(p.VelaAddr, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+p.VelaToken)
+
+	start := time.Now()
+
+	resp, err := p.httpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send request: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	logrus.WithFields(deliveryFields(urlHost(p.VelaAddr), 1, start)).Debugf("Received status %d from Vela API", resp.StatusCode)
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return fmt.Errorf("received status %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(v)
+	if err != nil {
+		return fmt.Errorf("unable to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// stepDuration returns the duration of a finished step.
+func stepDuration(started, finished int64) string {
+	if started == 0 || finished < started {
+		return ""
+	}
+
+	return (time.Duration(finished-started) * time.Second).String()
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+// newVelaServer creates a Vela API server returning the steps of build 1.
+func newVelaServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/v1/repos/octocat/hello-world/builds/1/steps", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer vela_token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+
+			return
+		}
+
+		fmt.Fprint(w, `[
+			{"number": 1, "name": "clone", "stage": "init", "status": "success", "started": 1563474076, "finished": 1563474078},
+			{"number": 2, "name": "test", "stage": "test", "status": "failure", "error": "exit code 1", "started": 1563474078, "finished": 1563474140},
+			{"number": 3, "name": "notify", "stage": "notify", "status": "running", "started": 1563474140}
+		]`)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestSlack_Plugin_steps(t *testing.T) {
+	// setup types
+	ts := newVelaServer(t)
+	defer ts.Close()
+
+	want := []Step{
+		{Number: 1, Name: "clone", Stage: "init", Status: "success", Duration: "2s"},
+		{Number: 2, Name: "test", Stage: "test", Status: "failure", Duration: "1m2s", Error: "exit code 1"},
+		{Number: 3, Name: "notify", Stage: "notify", Status: "running"},
+	}
+
+	// setup tests
+	tests := []struct {
+		addr    string
+		token   string
+		failure bool
+	}{
+		{addr: ts.URL, token: "vela_token"},
+		{addr: ts.URL, token: "invalid", failure: true},
+		{addr: "", token: "vela_token", failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		p := &Plugin{
+			VelaAPI:   true,
+			VelaAddr:  test.addr,
+			VelaToken: test.token,
+			Env: &Env{
+				BuildNumber:    1,
+				RepositoryOrg:  "octocat",
+				RepositoryName: "hello-world",
+			},
+		}
+
+		got, err := p.steps(context.Background())
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("steps should have returned err for %q", test.addr)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("steps returned err: %v", err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("steps is %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestSlack_Plugin_Exec_Steps(t *testing.T) {
+	// setup types
+	vela := newVelaServer(t)
+	defer vela.Close()
+
+	var posted slack.WebhookMessage
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&posted)
+		if err != nil {
+			t.Errorf("Decode error: %v", err)
+		}
+	}))
+	defer ts.Close()
+
+	p := &Plugin{
+		Webhook:   ts.URL,
+		VelaAPI:   true,
+		VelaAddr:  vela.URL,
+		VelaToken: "vela_token",
+		Env: &Env{
+			BuildNumber:    1,
+			RepositoryOrg:  "octocat",
+			RepositoryName: "hello-world",
+		},
+		WebhookMsg: &slack.WebhookMessage{
+			Text: "{{ range .Steps }}{{ if eq .Status \"failure\" }}{{ .Name }} failed after {{ .Duration }}: {{ .Error }}{{ end }}{{ end }}",
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	want := "test failed after 1m2s: exit code 1"
+
+	if posted.Text != want {
+		t.Errorf("Exec posted text %s, want %s", posted.Text, want)
+	}
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sendWebhook renders the body template and sends
+// it to the webhook with the method and headers.
+func (p *Plugin) sendWebhook(ctx context.Context) error {
+	// add the build context used by the template
+	p.loadEnv()
+
+	header, err := parseHeaders(p.WebhookHeaders)
+	if err != nil {
+		return classify(classConfig, err)
+	}
+
+	if len(header.Get("Content-Type")) == 0 {
+		header.Set("Content-Type", "application/json")
+	}
+
+	logrus.Info("Execute template conversion on webhook body...")
+
+	body, err := renderTemplate("body", p.WebhookBody, p.Env)
+	if err != nil {
+		return classify(classTemplate, err)
+	}
+
+	// remove secret values that were rendered into the body
+	body = p.redact(body)
+
+	method := strings.ToUpper(p.WebhookMethod)
+	if len(method) == 0 {
+		method = http.MethodPost
+	}
+
+	logrus.Infof("Sending %s request to webhook...", method)
+
+	start := time.Now()
+
+	err = sendRequest(ctx, p.httpClient(), method, p.Webhook, header, []byte(body))
+
+	fields := deliveryFields(urlHost(p.Webhook), 1, start)
+	if err != nil {
+		logrus.WithFields(fields).Warn("Unable to deliver webhook request")
+
+		return classify(classDelivery, fmt.Errorf("unable to send webhook request: %w", err))
+	}
+
+	logrus.WithFields(fields).Info("Delivered webhook request")
+
+	return nil
+}
+
+// parseHeaders parses the headers provided in the Name: value format.
+func parseHeaders(headers []string) (http.Header, error) {
+	header := http.Header{}
+
+	for _, h := range headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || len(strings.TrimSpace(name)) == 0 {
+			return nil, fmt.Errorf("invalid webhook header provided: %s", h)
+		}
+
+		header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	return header, nil
+}
+
+// validateWebhook validates the configuration for the webhook provider.
+func (p *Plugin) validateWebhook() error {
+	// validate the method option and that a body is sent with it
+	switch strings.ToUpper(p.WebhookMethod) {
+	case "", http.MethodPost, http.MethodPut, http.MethodPatch:
+		if len(p.WebhookBody) == 0 {
+			return fmt.Errorf("no webhook body provided")
+		}
+	case http.MethodGet, http.MethodDelete:
+	default:
+		return fmt.Errorf("invalid webhook method provided: %s", p.WebhookMethod)
+	}
+
+	_, err := parseHeaders(p.WebhookHeaders)
+	if err != nil {
+		return err
+	}
+
+	// validate that options requiring the Slack API weren't provided
+	if len(p.Files) > 0 || len(p.PostAt) > 0 || p.DMAuthor || len(p.EphemeralUser) > 0 {
+		return fmt.Errorf("unable to use Slack API options with the %s provider", p.Provider)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_Plugin_Exec_Webhook(t *testing.T) {
+	// setup types
+	var (
+		method string
+		header http.Header
+		body   string
+	)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("ReadAll error: %v", err)
+		}
+
+		method = r.Method
+		header = r.Header
+		body = string(b)
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	p := &Plugin{
+		Webhook:        ts.URL,
+		Provider:       providerWebhook,
+		WebhookMethod:  "put",
+		WebhookHeaders: []string{"Authorization: Token abc123", "X-Vela-Build: 1"},
+		WebhookBody:    `{"summary": "{{ .RepositoryFullName }} {{ .BuildStatus | upper }}", "message": "{{ .BuildMessage }}"}`,
+		Env: &Env{
+			BuildMessage:       "Update README\n\nFixes \"typo\"",
+			BuildStatus:        "failure",
+			RepositoryFullName: "octocat/hello-world",
+		},
+		WebhookMsg: &slack.WebhookMessage{},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	if method != http.MethodPut {
+		t.Errorf("Exec sent method %s, want %s", method, http.MethodPut)
+	}
+
+	if header.Get("Authorization") != "Token abc123" {
+		t.Errorf("Exec sent Authorization header %s", header.Get("Authorization"))
+	}
+
+	if header.Get("Content-Type") != "application/json" {
+		t.Errorf("Exec sent Content-Type header %s", header.Get("Content-Type"))
+	}
+
+	want := `{"summary": "octocat/hello-world FAILURE", "message": "Update README\n\nFixes \"typo\""}`
+
+	if body != want {
+		t.Errorf("Exec sent body %s, want %s", body, want)
+	}
+}
+
+func TestSlack_Plugin_Exec_Webhook_Error_Status(t *testing.T) {
+	// setup types
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	p := &Plugin{
+		Webhook:     ts.URL,
+		Provider:    providerWebhook,
+		WebhookBody: `{}`,
+		Env:         &Env{},
+		WebhookMsg:  &slack.WebhookMessage{},
+	}
+
+	err := p.Exec()
+	if err == nil {
+		t.Error("Exec should return err due to unauthorized status")
+	}
+}
+
+func TestSlack_Plugin_Validate_Webhook(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		p       *Plugin
+		failure bool
+	}{
+		{
+			name: "body without text",
+			p:    &Plugin{Webhook: "webhook_url", WebhookBody: `{}`},
+		},
+		{
+			name:    "missing webhook",
+			p:       &Plugin{BotToken: "xoxb-token", WebhookBody: `{}`},
+			failure: true,
+		},
+		{
+			name: "get without body",
+			p:    &Plugin{Webhook: "webhook_url", WebhookMethod: "get"},
+		},
+		{
+			name:    "missing body",
+			p:       &Plugin{Webhook: "webhook_url"},
+			failure: true,
+		},
+		{
+			name:    "missing body for put",
+			p:       &Plugin{Webhook: "webhook_url", WebhookMethod: "PUT"},
+			failure: true,
+		},
+		{
+			name:    "invalid method",
+			p:       &Plugin{Webhook: "webhook_url", WebhookMethod: "TRACE"},
+			failure: true,
+		},
+		{
+			name:    "invalid header",
+			p:       &Plugin{Webhook: "webhook_url", WebhookBody: `{}`, WebhookHeaders: []string{"Authorization"}},
+			failure: true,
+		},
+		{
+			name:    "files",
+			p:       &Plugin{Webhook: "webhook_url", WebhookBody: `{}`, BotToken: "xoxb-token", Files: []string{"*.xml"}},
+			failure: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		test.p.Provider = providerWebhook
+		test.p.Env = &Env{}
+		test.p.WebhookMsg = &slack.WebhookMessage{}
+
+		err := test.p.Validate()
+
+		if test.failure && err == nil {
+			t.Errorf("Validate should have returned err for %s", test.name)
+		}
+
+		if !test.failure && err != nil {
+			t.Errorf("Validate returned err for %s: %v", test.name, err)
+		}
+	}
+}
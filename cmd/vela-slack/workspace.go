@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxLineLength is the longest log line read from the log file.
+const maxLineLength = 1024 * 1024
+
+type (
+	// TestSummary represents the results parsed from JUnit XML reports.
+	TestSummary struct {
+		Total       int
+		Passed      int
+		Failed      int
+		Skipped     int
+		FailedTests []string
+	}
+
+	// junitSuite represents a testsuites or testsuite element in a JUnit XML report.
+	junitSuite struct {
+		Suites []junitSuite `xml:"testsuite"`
+		Cases  []junitCase  `xml:"testcase"`
+	}
+
+	// junitCase represents a testcase element in a JUnit XML report.
+	junitCase struct {
+		Name      string    `xml:"name,attr"`
+		Classname string    `xml:"classname,attr"`
+		Failure   *struct{} `xml:"failure"`
+		Error     *struct{} `xml:"error"`
+		Skipped   *struct{} `xml:"skipped"`
+	}
+)
+
+// loadWorkspace reads the log file and JUnit reports from the
+// workspace and adds the results to the environment. Problems
+// reading the files are logged so the message is still sent.
+func (p *Plugin) loadWorkspace() {
+	if len(p.LogFile) > 0 {
+		tail, err := p.tailLogFile()
+		if err != nil {
+			logrus.Warnf("unable to read log file: %v", err)
+		}
+
+		// the template is rendered inside JSON so the lines must be escaped
+		p.Env.LogTail = escapeJSON(tail)
+	}
+
+	if len(p.JUnit) > 0 {
+		files, err := matchFiles(p.Env.BuildWorkspace, p.JUnit)
+		if err != nil {
+			logrus.Warnf("unable to find junit reports: %v", err)
+		}
+
+		summary, err := summarizeJUnit(files)
+		if err != nil {
+			logrus.Warnf("unable to parse junit reports: %v", err)
+		}
+
+		for i, name := range summary.FailedTests {
+			summary.FailedTests[i] = escapeJSON(name)
+		}
+
+		p.Env.Tests = summary
+	}
+}
+
+// tailLogFile returns the last lines of the log file in the workspace.
+func (p *Plugin) tailLogFile() (string, error) {
+	path, err := workspaceFile(p.Env.BuildWorkspace, p.LogFile)
+	if err != nil {
+		return "", err
+	}
+
+	err = checkWorkspaceFile(p.Env.BuildWorkspace, path)
+	if err != nil {
+		return "", err
+	}
+
+	return tailFile(path, p.LogLines)
+}
+
+// tailFile returns the last n lines of the file.
+func tailFile(path string, n int) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to open file %s: %w", path, err)
+	}
+
+	defer f.Close()
+
+	if n <= 0 {
+		return "", nil
+	}
+
+	lines := make([]string, 0, n)
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineLength)
+
+	for scanner.Scan() {
+		if len(lines) == n {
+			lines = lines[1:]
+		}
+
+		lines = append(lines, scanner.Text())
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return "", fmt.Errorf("unable to read file %s: %w", path, err)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// summarizeJUnit counts the test results from the JUnit XML reports.
+func summarizeJUnit(files []string) (TestSummary, error) {
+	var summary TestSummary
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return summary, fmt.Errorf("unable to read file %s: %w", file, err)
+		}
+
+		var suite junitSuite
+
+		err = xml.Unmarshal(data, &suite)
+		if err != nil {
+			return summary, fmt.Errorf("unable to parse file %s: %w", file, err)
+		}
+
+		suite.summarize(&summary)
+	}
+
+	return summary, nil
+}
+
+// summarize adds the test cases of the suite and any nested suites to the summary.
+func (s *junitSuite) summarize(summary *TestSummary) {
+	for _, c := range s.Cases {
+		summary.Total++
+
+		switch {
+		case c.Failure != nil, c.Error != nil:
+			summary.Failed++
+
+			name := c.Name
+			if len(c.Classname) > 0 {
+				name = c.Classname + "." + c.Name
+			}
+
+			summary.FailedTests = append(summary.FailedTests, name)
+		case c.Skipped != nil:
+			summary.Skipped++
+		default:
+			summary.Passed++
+		}
+	}
+
+	for i := range s.Suites {
+		s.Suites[i].summarize(summary)
+	}
+}
+
+// workspacePath returns the path relative to the workspace
+// unless the path is absolute or no workspace is provided.
+func workspacePath(workspace, path string) string {
+	if filepath.IsAbs(path) || len(workspace) == 0 {
+		return path
+	}
+
+	return filepath.Join(workspace, path)
+}
+
+// workspaceFile returns the path of a file in the workspace that is sent
+// to a provider. Absolute paths and paths leaving the workspace are
+// rejected so files from the runner, like secrets, can't be sent.
+func workspaceFile(workspace, path string) (string, error) {
+	clean := filepath.Clean(path)
+
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path outside the workspace: %s", path)
+	}
+
+	return filepath.Join(workspace, clean), nil
+}
+
+// checkWorkspaceFile validates that the file resolves inside
+// the workspace after following symbolic links.
+func checkWorkspaceFile(workspace, file string) error {
+	if len(workspace) == 0 {
+		workspace = "."
+	}
+
+	root, err := filepath.EvalSymlinks(workspace)
+	if err != nil {
+		return fmt.Errorf("unable to resolve workspace %s: %w", workspace, err)
+	}
+
+	resolved, err := filepath.EvalSymlinks(file)
+	if err != nil {
+		return fmt.Errorf("unable to resolve file %s: %w", file, err)
+	}
+
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return fmt.Errorf("unable to resolve workspace %s: %w", workspace, err)
+	}
+
+	resolved, err = filepath.Abs(resolved)
+	if err != nil {
+		return fmt.Errorf("unable to resolve file %s: %w", file, err)
+	}
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("path outside the workspace: %s", file)
+	}
+
+	return nil
+}
+
+// escapeJSON escapes the string so it can be placed inside a JSON string.
+// HTML characters are kept as is so template functions like truncate
+// don't cut inside a \u003c escape.
+func escapeJSON(s string) string {
+	var b bytes.Buffer
+
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+
+	err := enc.Encode(s)
+	if err != nil {
+		return s
+	}
+
+	// remove the quotes and the newline added by the encoder
+	out := bytes.TrimSuffix(b.Bytes(), []byte("\n"))
+
+	return string(out[1 : len(out)-1])
+}
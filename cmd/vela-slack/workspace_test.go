@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlack_tailFile(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{n: 0, want: ""},
+		{n: 2, want: "step 3\nerror: \"unexpected\"\tvalue"},
+		{n: 10, want: "step 1\nstep 2\nstep 3\nerror: \"unexpected\"\tvalue"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := tailFile("testdata/build.log", test.n)
+		if err != nil {
+			t.Errorf("tailFile returned err: %v", err)
+		}
+
+		if got != test.want {
+			t.Errorf("tailFile is %q, want %q", got, test.want)
+		}
+	}
+
+	_, err := tailFile("testdata/404.log", 1)
+	if err == nil {
+		t.Error("tailFile should return err due to missing file")
+	}
+}
+
+func TestSlack_Plugin_tailLogFile(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		workspace string
+		logFile   string
+		failure   bool
+	}{
+		{workspace: "testdata", logFile: "build.log"},
+		{workspace: "testdata", logFile: "./logs/../build.log"},
+		{workspace: "testdata", logFile: "/etc/passwd", failure: true},
+		{workspace: "testdata", logFile: "../workspace.go", failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		p := &Plugin{
+			LogFile:  test.logFile,
+			LogLines: 1,
+			Env:      &Env{BuildWorkspace: test.workspace},
+		}
+
+		_, err := p.tailLogFile()
+
+		if test.failure && err == nil {
+			t.Errorf("tailLogFile should have returned err for %s", test.logFile)
+		}
+
+		if !test.failure && err != nil {
+			t.Errorf("tailLogFile returned err for %s: %v", test.logFile, err)
+		}
+	}
+}
+
+func TestSlack_summarizeJUnit(t *testing.T) {
+	// setup types
+	want := TestSummary{
+		Total:       4,
+		Passed:      1,
+		Failed:      2,
+		Skipped:     1,
+		FailedTests: []string{"hello.TestWorld", "TestPanic"},
+	}
+
+	got, err := summarizeJUnit([]string{"testdata/junit_report.xml"})
+	if err != nil {
+		t.Errorf("summarizeJUnit returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("summarizeJUnit is %v, want %v", got, want)
+	}
+
+	_, err = summarizeJUnit([]string{"testdata/slack_attachment_bad.json"})
+	if err == nil {
+		t.Error("summarizeJUnit should return err due to invalid XML")
+	}
+}
+
+func TestSlack_Plugin_Exec_Workspace(t *testing.T) {
+	// setup types
+	var posted slack.WebhookMessage
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&posted)
+		if err != nil {
+			t.Errorf("Decode error: %v", err)
+		}
+
+		fmt.Fprintln(w, "ok")
+	}))
+	defer ts.Close()
+
+	p := &Plugin{
+		Webhook:  ts.URL,
+		Env:      &Env{},
+		LogFile:  "testdata/build.log",
+		LogLines: 1,
+		JUnit:    []string{"testdata/*.xml"},
+		WebhookMsg: &slack.WebhookMessage{
+			Text: "{{ .Tests.Failed }}/{{ .Tests.Total }} failed: {{ join \", \" .Tests.FailedTests }}\n```{{ .LogTail }}```",
+		},
+	}
+
+	err := p.Exec()
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	want := "2/4 failed: hello.TestWorld, TestPanic\n```error: \"unexpected\"\tvalue```"
+	if posted.Text != want {
+		t.Errorf("Exec posted text %q, want %q", posted.Text, want)
+	}
+}
+
+func TestSlack_escapeJSON(t *testing.T) {
+	// setup types
+	input := "<a href=\"https://vela\">build</a>\n\x01done"
+
+	// run test
+	got := escapeJSON(input)
+
+	want := `<a href=\"https://vela\">build</a>\n\u0001done`
+	if got != want {
+		t.Errorf("escapeJSON is %s, want %s", got, want)
+	}
+
+	// truncating the escaped text always leaves valid JSON
+	for n := range len(got) {
+		var s string
+
+		err := json.Unmarshal([]byte(`"`+truncate(n, got)+`"`), &s)
+		if err != nil {
+			t.Errorf("truncate %d of escaped text returned invalid JSON: %v", n, err)
+		}
+	}
+}